@@ -0,0 +1,267 @@
+// Package aggregator quotes token swaps across Uniswap V2-style DEXes from
+// on-chain reserves, optionally enriched with subgraph data. It is the
+// library behind the HTTP and gRPC server and the command-line client, and
+// can be embedded by other Go services:
+//
+//	agg, err := aggregator.New(aggregator.WithRPCURL(url))
+//	if err != nil { ... }
+//	go agg.Run(ctx)
+//	quote, err := agg.Quote(ctx, "WETH", "USDC", big.NewInt(1e18))
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/ethereum"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/thegraph"
+	"github.com/DiDinar5/mini-dex-aggregator/internal/health"
+	"github.com/DiDinar5/mini-dex-aggregator/internal/poolgraph"
+	"github.com/DiDinar5/mini-dex-aggregator/internal/usecase"
+)
+
+// Quote is the result of Aggregator.Quote: the best pool and every other
+// quoted pool, with amounts in base units.
+type Quote = domain.SwapQuote
+
+// PoolQuote is what one pool pays out for a quoted amount.
+type PoolQuote = domain.PoolQuote
+
+// Estimate is the result of Aggregator.Estimate.
+type Estimate = domain.SwapEstimate
+
+// Error is returned for invalid input and failed upstream calls; its Code
+// tells them apart.
+type Error = domain.Error
+
+// Aggregator owns the chain client, the graph source, the token registry and
+// the background loops that keep the pool graph and chain head current.
+type Aggregator struct {
+	client      domain.EthereumServiceInterface
+	graphSource domain.TheGraphServiceInterface
+	cache       *thegraph.CachedGraphService
+	poolGraph   *poolgraph.Graph
+	crawler     *poolgraph.Crawler
+	blocks      *ethereum.BlockWatcher
+	gasOracle   *ethereum.GasOracle
+	checker     *health.Checker
+	tokens      *ethereum.TokenRegistry
+	settings    *usecase.Settings
+	usecase     *usecase.CombinedUsecase
+}
+
+// New builds an Aggregator. A chain client, from WithRPCURL or
+// WithEthereumClient, is required; everything else is optional.
+func New(opts ...Option) (*Aggregator, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.gasOracle.BlockCount <= 0 {
+		return nil, errors.New("WithGasOracle needs a positive block count")
+	}
+
+	client, err := newClient(o)
+	if err != nil {
+		return nil, err
+	}
+
+	graphSource := o.graphSource
+	switch {
+	case graphSource != nil && len(o.endpoints) > 0:
+		return nil, errors.New("WithGraphSource and WithSubgraphs are mutually exclusive")
+	case len(o.endpoints) > 0:
+		graphSource = thegraph.NewTheGraphService(o.endpoints, o.minTVL, o.graphOptions)
+	}
+	if o.crawlInterval > 0 && graphSource == nil {
+		return nil, errors.New("WithPoolCrawl needs WithSubgraphs or WithGraphSource")
+	}
+
+	a := &Aggregator{
+		client:      client,
+		graphSource: graphSource,
+		poolGraph:   poolgraph.NewGraph(),
+		blocks:      ethereum.NewBlockWatcher(client, o.blockPollInterval),
+		tokens:      ethereum.NewTokenRegistry(o.tokens),
+	}
+	a.settings = usecase.NewSettings(o.minTVL, a.tokens)
+
+	// Quotes read through the cache; the crawler and health probes go
+	// straight to the source.
+	quoteSource := graphSource
+	if o.cache != nil && graphSource != nil {
+		a.cache = thegraph.NewCachedGraphService(graphSource, *o.cache)
+		quoteSource = a.cache
+	}
+	if source, ok := client.(ethereum.FeeHistorySource); ok {
+		a.gasOracle = ethereum.NewGasOracle(source, a.blocks, o.gasOracle)
+	}
+	gasSource := o.gasSource
+	if !o.gasSourceSet && a.gasOracle != nil {
+		gasSource = a.gasOracle
+	}
+	a.usecase = usecase.NewUsecase(client, quoteSource, a.poolGraph, a.settings, gasSource)
+
+	// Leave out the probes for features that are switched off.
+	var subgraphHeads domain.SubgraphHeadSource
+	if heads, ok := graphSource.(domain.SubgraphHeadSource); ok {
+		subgraphHeads = heads
+	}
+	var warmPoolGraph domain.PoolGraphInterface
+	if o.crawlInterval > 0 {
+		a.crawler = poolgraph.NewCrawler(graphSource, a.poolGraph, o.crawlInterval)
+		warmPoolGraph = a.poolGraph
+	}
+	a.checker = health.NewChecker(chainHead(client), subgraphHeads, warmPoolGraph, health.Options{
+		MaxHeadAge:     o.maxHeadAge,
+		MaxSubgraphLag: o.maxSubgraphLag,
+		Timeout:        o.probeTimeout,
+	})
+
+	return a, nil
+}
+
+func newClient(o options) (domain.EthereumServiceInterface, error) {
+	switch {
+	case o.client != nil && o.rpcURL != "":
+		return nil, errors.New("WithEthereumClient and WithRPCURL are mutually exclusive")
+	case o.client != nil:
+		if len(o.dexes) > 0 {
+			return nil, errors.New("WithDEX needs WithRPCURL")
+		}
+		return o.client, nil
+	case o.rpcURL == "":
+		return nil, errors.New("an Ethereum RPC URL or client is required")
+	}
+
+	service, err := ethereum.NewEthereumService(o.rpcURL, o.rpcTimeout)
+	if err != nil {
+		return nil, err
+	}
+	for name, factoryAddress := range o.dexes {
+		if err := service.AddDEX(name, factoryAddress); err != nil {
+			return nil, err
+		}
+	}
+
+	return service, nil
+}
+
+// Run polls the chain head and, with WithPoolCrawl, crawls the pool graph
+// until ctx is done. Call it once. Quotes work without it, but Blocks stays
+// silent and the pool graph empty.
+func (a *Aggregator) Run(ctx context.Context) {
+	if a.crawler != nil {
+		go a.crawler.Run(ctx)
+	}
+	a.blocks.Run(ctx)
+}
+
+// Quote finds the pool that pays out the most for amountIn base units of
+// from, where from and to are registry symbols or token addresses. Every
+// pool is read at the same block.
+func (a *Aggregator) Quote(ctx context.Context, from, to string, amountIn *big.Int) (Quote, error) {
+	return a.usecase.SwapQuote(ctx, from, to, amountIn)
+}
+
+// Estimate computes what swapping amountIn base units of the src token
+// through the Uniswap V2 pool at pool pays out in dst, both given by
+// address.
+func (a *Aggregator) Estimate(ctx context.Context, pool, src, dst string, amountIn *big.Int) (Estimate, error) {
+	return a.usecase.SwapEstimate(ctx, pool, src, dst, amountIn)
+}
+
+// Usecase serves the request and response types of the HTTP and gRPC APIs,
+// with amounts formatted as decimal strings.
+func (a *Aggregator) Usecase() domain.UsecaseInterface {
+	return a.usecase
+}
+
+// Blocks notifies subscribers of every new chain head while Run is active.
+func (a *Aggregator) Blocks() domain.BlockSourceInterface {
+	return a.blocks
+}
+
+// CacheStats reports the graph cache counters, or nil without WithCache.
+func (a *Aggregator) CacheStats() domain.CacheStatsProvider {
+	if a.cache == nil {
+		return nil
+	}
+	return a.cache
+}
+
+// GasOracle suggests transaction fees from the recent fee history, or is
+// nil when the chain client cannot read it.
+func (a *Aggregator) GasOracle() domain.GasSuggestionProvider {
+	if a.gasOracle == nil {
+		return nil
+	}
+	return a.gasOracle
+}
+
+// RefreshPools crawls the graph source once, for callers that need the
+// pool graph without running the periodic crawl.
+func (a *Aggregator) RefreshPools(ctx context.Context) error {
+	if a.graphSource == nil {
+		return errors.New("listing pools needs a graph source")
+	}
+	poolgraph.NewCrawler(a.graphSource, a.poolGraph, 0).Refresh(ctx)
+	return nil
+}
+
+// PoolsCrawled reports whether the pool graph holds a crawl yet.
+func (a *Aggregator) PoolsCrawled() bool {
+	return !a.poolGraph.UpdatedAt().IsZero()
+}
+
+// Readiness probes the chain client, the subgraphs and the pool graph.
+func (a *Aggregator) Readiness(ctx context.Context) domain.ReadinessResponse {
+	return a.checker.Readiness(ctx)
+}
+
+// SetDraining makes Readiness report draining from now on, ahead of a
+// shutdown.
+func (a *Aggregator) SetDraining() {
+	a.checker.SetDraining()
+}
+
+// SetMinTVL changes the quoting liquidity threshold. It is safe to call
+// while quotes are in flight.
+func (a *Aggregator) SetMinTVL(minTVL float64) {
+	a.settings.SetMinTVL(minTVL)
+	if source, ok := a.graphSource.(interface{ SetMinTVL(float64) }); ok {
+		source.SetMinTVL(minTVL)
+	}
+}
+
+// SetTokens replaces the tokens added by WithTokens. It is safe to call
+// while quotes are in flight.
+func (a *Aggregator) SetTokens(tokens map[string]string) {
+	a.tokens.Set(tokens)
+}
+
+// chainHead adapts clients that cannot read block headers, whose head age
+// is then not checked.
+func chainHead(client domain.EthereumServiceInterface) domain.ChainHeadSource {
+	if head, ok := client.(domain.ChainHeadSource); ok {
+		return head
+	}
+	return blockNumberHead{client}
+}
+
+type blockNumberHead struct {
+	client domain.EthereumServiceInterface
+}
+
+func (h blockNumberHead) HeadBlock(ctx context.Context) (domain.BlockHeader, error) {
+	number, err := h.client.BlockNumber(ctx)
+	if err != nil {
+		return domain.BlockHeader{}, fmt.Errorf("failed to get block number: %w", err)
+	}
+	return domain.BlockHeader{Number: number, Time: time.Now()}, nil
+}
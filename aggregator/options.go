@@ -0,0 +1,172 @@
+package aggregator
+
+import (
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/ethereum"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/thegraph"
+)
+
+// Option configures an Aggregator built by New.
+type Option func(*options)
+
+type options struct {
+	rpcURL     string
+	rpcTimeout time.Duration
+	client     domain.EthereumServiceInterface
+	dexes      map[string]string
+
+	tokens map[string]string
+	minTVL float64
+
+	gasSource    domain.GasPriceSource
+	gasSourceSet bool
+	gasOracle    ethereum.GasOracleOptions
+
+	endpoints     []thegraph.Endpoint
+	graphOptions  thegraph.Options
+	graphSource   domain.TheGraphServiceInterface
+	cache         *thegraph.CacheOptions
+	crawlInterval time.Duration
+
+	blockPollInterval time.Duration
+
+	maxHeadAge     time.Duration
+	maxSubgraphLag uint64
+	probeTimeout   time.Duration
+}
+
+func defaultOptions() options {
+	return options{
+		rpcTimeout:        30 * time.Second,
+		gasOracle:         ethereum.DefaultGasOracleOptions(),
+		minTVL:            10000.0,
+		blockPollInterval: 2 * time.Second,
+		maxHeadAge:        2 * time.Minute,
+		maxSubgraphLag:    50,
+		probeTimeout:      3 * time.Second,
+	}
+}
+
+// WithRPCURL connects to the Ethereum node at url, over HTTP or WebSocket.
+func WithRPCURL(url string) Option {
+	return func(o *options) {
+		o.rpcURL = url
+	}
+}
+
+// WithRPCTimeout bounds the connection attempt and every RPC call made
+// through WithRPCURL. Zero means no limit; the default is 30 seconds.
+func WithRPCTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.rpcTimeout = timeout
+	}
+}
+
+// WithEthereumClient reads the chain through client instead of dialing a
+// node, e.g. to share one connection or to serve a test double.
+func WithEthereumClient(client domain.EthereumServiceInterface) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithDEX adds a Uniswap V2-style DEX whose pairs are created by the
+// factory at factoryAddress, next to the built-in UniswapV2 and Sushiswap.
+// It needs WithRPCURL, since a custom client finds pools on its own.
+func WithDEX(name, factoryAddress string) Option {
+	return func(o *options) {
+		if o.dexes == nil {
+			o.dexes = make(map[string]string)
+		}
+		o.dexes[name] = factoryAddress
+	}
+}
+
+// WithTokens adds symbols to the built-in token registry, mapping each onto
+// its contract address. Built-in symbols may be overridden.
+func WithTokens(tokens map[string]string) Option {
+	return func(o *options) {
+		o.tokens = tokens
+	}
+}
+
+// WithMinTVL sets the USD liquidity below which pools are not quoted. The
+// default is $10,000.
+func WithMinTVL(minTVL float64) Option {
+	return func(o *options) {
+		o.minTVL = minTVL
+	}
+}
+
+// WithGasPriceSource prices the gas of quoted routes at the fees source
+// suggests, so quotes are ranked by output net of gas. By default the gas
+// oracle's standard tier is used; a client set with WithEthereumClient that
+// cannot read fee history ranks by gross output, as does a nil source.
+func WithGasPriceSource(source domain.GasPriceSource) Option {
+	return func(o *options) {
+		o.gasSource = source
+		o.gasSourceSet = true
+	}
+}
+
+// WithGasOracle sets the fee history window and tier percentiles of the
+// gas oracle. The default is DefaultGasOracleOptions.
+func WithGasOracle(oracleOptions ethereum.GasOracleOptions) Option {
+	return func(o *options) {
+		o.gasOracle = oracleOptions
+	}
+}
+
+// WithSubgraphs enriches quotes with pool data from the given subgraphs and
+// makes them the source of the pool graph.
+func WithSubgraphs(endpoints []thegraph.Endpoint, graphOptions thegraph.Options) Option {
+	return func(o *options) {
+		o.endpoints = endpoints
+		o.graphOptions = graphOptions
+	}
+}
+
+// WithGraphSource supplies pool data from source instead of subgraphs
+// built by WithSubgraphs.
+func WithGraphSource(source domain.TheGraphServiceInterface) Option {
+	return func(o *options) {
+		o.graphSource = source
+	}
+}
+
+// WithCache caches graph source responses in memory.
+func WithCache(cacheOptions thegraph.CacheOptions) Option {
+	return func(o *options) {
+		o.cache = &cacheOptions
+	}
+}
+
+// WithPoolCrawl makes Run crawl every pool of the graph source on each
+// interval. The crawled pool graph is then the candidate universe for
+// quotes and the pool listing.
+func WithPoolCrawl(interval time.Duration) Option {
+	return func(o *options) {
+		o.crawlInterval = interval
+	}
+}
+
+// WithBlockPollInterval sets how often Run polls the chain head for the
+// block source. The default is 2 seconds.
+func WithBlockPollInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.blockPollInterval = interval
+	}
+}
+
+// WithHealthLimits sets what Readiness tolerates: how old the chain head
+// may be, how many blocks a subgraph may trail it, and how long the probes
+// may take together.
+func WithHealthLimits(maxHeadAge time.Duration, maxSubgraphLag uint64, probeTimeout time.Duration) Option {
+	return func(o *options) {
+		o.maxHeadAge = maxHeadAge
+		o.maxSubgraphLag = maxSubgraphLag
+		o.probeTimeout = probeTimeout
+	}
+}
@@ -0,0 +1,1235 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: aggregator.proto
+
+package aggregatorv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type QuoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Amount        string                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuoteRequest) Reset() {
+	*x = QuoteRequest{}
+	mi := &file_aggregator_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteRequest) ProtoMessage() {}
+
+func (x *QuoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregator_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteRequest.ProtoReflect.Descriptor instead.
+func (*QuoteRequest) Descriptor() ([]byte, []int) {
+	return file_aggregator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *QuoteRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *QuoteRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *QuoteRequest) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+// QuoteResponse ranks pools by output net of gas. net_to_amount and gas are
+// unset when gas could not be priced, and pools are then ranked by
+// to_amount.
+type QuoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromToken     string                 `protobuf:"bytes,1,opt,name=from_token,json=fromToken,proto3" json:"from_token,omitempty"`
+	ToToken       string                 `protobuf:"bytes,2,opt,name=to_token,json=toToken,proto3" json:"to_token,omitempty"`
+	FromAmount    string                 `protobuf:"bytes,3,opt,name=from_amount,json=fromAmount,proto3" json:"from_amount,omitempty"`
+	ToAmount      string                 `protobuf:"bytes,4,opt,name=to_amount,json=toAmount,proto3" json:"to_amount,omitempty"`
+	BestQuote     *DEXQuote              `protobuf:"bytes,5,opt,name=best_quote,json=bestQuote,proto3" json:"best_quote,omitempty"`
+	AllQuotes     []*DEXQuote            `protobuf:"bytes,6,rep,name=all_quotes,json=allQuotes,proto3" json:"all_quotes,omitempty"`
+	NetToAmount   string                 `protobuf:"bytes,7,opt,name=net_to_amount,json=netToAmount,proto3" json:"net_to_amount,omitempty"`
+	Gas           *GasInfo               `protobuf:"bytes,8,opt,name=gas,proto3" json:"gas,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuoteResponse) Reset() {
+	*x = QuoteResponse{}
+	mi := &file_aggregator_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteResponse) ProtoMessage() {}
+
+func (x *QuoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregator_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteResponse.ProtoReflect.Descriptor instead.
+func (*QuoteResponse) Descriptor() ([]byte, []int) {
+	return file_aggregator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *QuoteResponse) GetFromToken() string {
+	if x != nil {
+		return x.FromToken
+	}
+	return ""
+}
+
+func (x *QuoteResponse) GetToToken() string {
+	if x != nil {
+		return x.ToToken
+	}
+	return ""
+}
+
+func (x *QuoteResponse) GetFromAmount() string {
+	if x != nil {
+		return x.FromAmount
+	}
+	return ""
+}
+
+func (x *QuoteResponse) GetToAmount() string {
+	if x != nil {
+		return x.ToAmount
+	}
+	return ""
+}
+
+func (x *QuoteResponse) GetBestQuote() *DEXQuote {
+	if x != nil {
+		return x.BestQuote
+	}
+	return nil
+}
+
+func (x *QuoteResponse) GetAllQuotes() []*DEXQuote {
+	if x != nil {
+		return x.AllQuotes
+	}
+	return nil
+}
+
+func (x *QuoteResponse) GetNetToAmount() string {
+	if x != nil {
+		return x.NetToAmount
+	}
+	return ""
+}
+
+func (x *QuoteResponse) GetGas() *GasInfo {
+	if x != nil {
+		return x.Gas
+	}
+	return nil
+}
+
+type DEXQuote struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Dex         string                 `protobuf:"bytes,1,opt,name=dex,proto3" json:"dex,omitempty"`
+	Pool        string                 `protobuf:"bytes,2,opt,name=pool,proto3" json:"pool,omitempty"`
+	ToAmount    string                 `protobuf:"bytes,3,opt,name=to_amount,json=toAmount,proto3" json:"to_amount,omitempty"`
+	Price       string                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	PoolInfo    *PoolInfo              `protobuf:"bytes,5,opt,name=pool_info,json=poolInfo,proto3" json:"pool_info,omitempty"`
+	NetToAmount string                 `protobuf:"bytes,6,opt,name=net_to_amount,json=netToAmount,proto3" json:"net_to_amount,omitempty"`
+	GasEstimate uint64                 `protobuf:"varint,7,opt,name=gas_estimate,json=gasEstimate,proto3" json:"gas_estimate,omitempty"`
+	// gas_cost is in wei.
+	GasCost       string `protobuf:"bytes,8,opt,name=gas_cost,json=gasCost,proto3" json:"gas_cost,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DEXQuote) Reset() {
+	*x = DEXQuote{}
+	mi := &file_aggregator_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DEXQuote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DEXQuote) ProtoMessage() {}
+
+func (x *DEXQuote) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregator_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DEXQuote.ProtoReflect.Descriptor instead.
+func (*DEXQuote) Descriptor() ([]byte, []int) {
+	return file_aggregator_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DEXQuote) GetDex() string {
+	if x != nil {
+		return x.Dex
+	}
+	return ""
+}
+
+func (x *DEXQuote) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *DEXQuote) GetToAmount() string {
+	if x != nil {
+		return x.ToAmount
+	}
+	return ""
+}
+
+func (x *DEXQuote) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *DEXQuote) GetPoolInfo() *PoolInfo {
+	if x != nil {
+		return x.PoolInfo
+	}
+	return nil
+}
+
+func (x *DEXQuote) GetNetToAmount() string {
+	if x != nil {
+		return x.NetToAmount
+	}
+	return ""
+}
+
+func (x *DEXQuote) GetGasEstimate() uint64 {
+	if x != nil {
+		return x.GasEstimate
+	}
+	return 0
+}
+
+func (x *DEXQuote) GetGasCost() string {
+	if x != nil {
+		return x.GasCost
+	}
+	return ""
+}
+
+// GasInfo reports the fees a quote's gas costs were priced at, in wei per
+// gas.
+type GasInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BaseFee       string                 `protobuf:"bytes,1,opt,name=base_fee,json=baseFee,proto3" json:"base_fee,omitempty"`
+	PriorityFee   string                 `protobuf:"bytes,2,opt,name=priority_fee,json=priorityFee,proto3" json:"priority_fee,omitempty"`
+	GasPrice      string                 `protobuf:"bytes,3,opt,name=gas_price,json=gasPrice,proto3" json:"gas_price,omitempty"`
+	BlockNumber   uint64                 `protobuf:"varint,4,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GasInfo) Reset() {
+	*x = GasInfo{}
+	mi := &file_aggregator_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GasInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GasInfo) ProtoMessage() {}
+
+func (x *GasInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregator_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GasInfo.ProtoReflect.Descriptor instead.
+func (*GasInfo) Descriptor() ([]byte, []int) {
+	return file_aggregator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GasInfo) GetBaseFee() string {
+	if x != nil {
+		return x.BaseFee
+	}
+	return ""
+}
+
+func (x *GasInfo) GetPriorityFee() string {
+	if x != nil {
+		return x.PriorityFee
+	}
+	return ""
+}
+
+func (x *GasInfo) GetGasPrice() string {
+	if x != nil {
+		return x.GasPrice
+	}
+	return ""
+}
+
+func (x *GasInfo) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+type PoolInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tvl           string                 `protobuf:"bytes,1,opt,name=tvl,proto3" json:"tvl,omitempty"`
+	TvlSource     string                 `protobuf:"bytes,2,opt,name=tvl_source,json=tvlSource,proto3" json:"tvl_source,omitempty"`
+	Volume_24H    string                 `protobuf:"bytes,3,opt,name=volume_24h,json=volume24h,proto3" json:"volume_24h,omitempty"`
+	Fees_24H      string                 `protobuf:"bytes,4,opt,name=fees_24h,json=fees24h,proto3" json:"fees_24h,omitempty"`
+	Reserve0      string                 `protobuf:"bytes,5,opt,name=reserve0,proto3" json:"reserve0,omitempty"`
+	Reserve1      string                 `protobuf:"bytes,6,opt,name=reserve1,proto3" json:"reserve1,omitempty"`
+	Token0Symbol  string                 `protobuf:"bytes,7,opt,name=token0_symbol,json=token0Symbol,proto3" json:"token0_symbol,omitempty"`
+	Token1Symbol  string                 `protobuf:"bytes,8,opt,name=token1_symbol,json=token1Symbol,proto3" json:"token1_symbol,omitempty"`
+	IsActive      bool                   `protobuf:"varint,9,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PoolInfo) Reset() {
+	*x = PoolInfo{}
+	mi := &file_aggregator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolInfo) ProtoMessage() {}
+
+func (x *PoolInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolInfo.ProtoReflect.Descriptor instead.
+func (*PoolInfo) Descriptor() ([]byte, []int) {
+	return file_aggregator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PoolInfo) GetTvl() string {
+	if x != nil {
+		return x.Tvl
+	}
+	return ""
+}
+
+func (x *PoolInfo) GetTvlSource() string {
+	if x != nil {
+		return x.TvlSource
+	}
+	return ""
+}
+
+func (x *PoolInfo) GetVolume_24H() string {
+	if x != nil {
+		return x.Volume_24H
+	}
+	return ""
+}
+
+func (x *PoolInfo) GetFees_24H() string {
+	if x != nil {
+		return x.Fees_24H
+	}
+	return ""
+}
+
+func (x *PoolInfo) GetReserve0() string {
+	if x != nil {
+		return x.Reserve0
+	}
+	return ""
+}
+
+func (x *PoolInfo) GetReserve1() string {
+	if x != nil {
+		return x.Reserve1
+	}
+	return ""
+}
+
+func (x *PoolInfo) GetToken0Symbol() string {
+	if x != nil {
+		return x.Token0Symbol
+	}
+	return ""
+}
+
+func (x *PoolInfo) GetToken1Symbol() string {
+	if x != nil {
+		return x.Token1Symbol
+	}
+	return ""
+}
+
+func (x *PoolInfo) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+type EstimateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	Src           string                 `protobuf:"bytes,2,opt,name=src,proto3" json:"src,omitempty"`
+	Dst           string                 `protobuf:"bytes,3,opt,name=dst,proto3" json:"dst,omitempty"`
+	SrcAmount     string                 `protobuf:"bytes,4,opt,name=src_amount,json=srcAmount,proto3" json:"src_amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EstimateRequest) Reset() {
+	*x = EstimateRequest{}
+	mi := &file_aggregator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EstimateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EstimateRequest) ProtoMessage() {}
+
+func (x *EstimateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EstimateRequest.ProtoReflect.Descriptor instead.
+func (*EstimateRequest) Descriptor() ([]byte, []int) {
+	return file_aggregator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *EstimateRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *EstimateRequest) GetSrc() string {
+	if x != nil {
+		return x.Src
+	}
+	return ""
+}
+
+func (x *EstimateRequest) GetDst() string {
+	if x != nil {
+		return x.Dst
+	}
+	return ""
+}
+
+func (x *EstimateRequest) GetSrcAmount() string {
+	if x != nil {
+		return x.SrcAmount
+	}
+	return ""
+}
+
+type EstimateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DstAmount     string                 `protobuf:"bytes,1,opt,name=dst_amount,json=dstAmount,proto3" json:"dst_amount,omitempty"`
+	PoolType      string                 `protobuf:"bytes,2,opt,name=pool_type,json=poolType,proto3" json:"pool_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EstimateResponse) Reset() {
+	*x = EstimateResponse{}
+	mi := &file_aggregator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EstimateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EstimateResponse) ProtoMessage() {}
+
+func (x *EstimateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EstimateResponse.ProtoReflect.Descriptor instead.
+func (*EstimateResponse) Descriptor() ([]byte, []int) {
+	return file_aggregator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EstimateResponse) GetDstAmount() string {
+	if x != nil {
+		return x.DstAmount
+	}
+	return ""
+}
+
+func (x *EstimateResponse) GetPoolType() string {
+	if x != nil {
+		return x.PoolType
+	}
+	return ""
+}
+
+// Error mirrors the REST ErrorResponse for per-item and streamed failures.
+type Error struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode     string                 `protobuf:"bytes,1,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_aggregator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_aggregator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Error) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type BatchQuoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Quotes        []*QuoteRequest        `protobuf:"bytes,1,rep,name=quotes,proto3" json:"quotes,omitempty"`
+	Estimates     []*EstimateRequest     `protobuf:"bytes,2,rep,name=estimates,proto3" json:"estimates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchQuoteRequest) Reset() {
+	*x = BatchQuoteRequest{}
+	mi := &file_aggregator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchQuoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchQuoteRequest) ProtoMessage() {}
+
+func (x *BatchQuoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchQuoteRequest.ProtoReflect.Descriptor instead.
+func (*BatchQuoteRequest) Descriptor() ([]byte, []int) {
+	return file_aggregator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchQuoteRequest) GetQuotes() []*QuoteRequest {
+	if x != nil {
+		return x.Quotes
+	}
+	return nil
+}
+
+func (x *BatchQuoteRequest) GetEstimates() []*EstimateRequest {
+	if x != nil {
+		return x.Estimates
+	}
+	return nil
+}
+
+type BatchQuoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BlockNumber   uint64                 `protobuf:"varint,1,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	Quotes        []*QuoteResult         `protobuf:"bytes,2,rep,name=quotes,proto3" json:"quotes,omitempty"`
+	Estimates     []*EstimateResult      `protobuf:"bytes,3,rep,name=estimates,proto3" json:"estimates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchQuoteResponse) Reset() {
+	*x = BatchQuoteResponse{}
+	mi := &file_aggregator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchQuoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchQuoteResponse) ProtoMessage() {}
+
+func (x *BatchQuoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchQuoteResponse.ProtoReflect.Descriptor instead.
+func (*BatchQuoteResponse) Descriptor() ([]byte, []int) {
+	return file_aggregator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchQuoteResponse) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *BatchQuoteResponse) GetQuotes() []*QuoteResult {
+	if x != nil {
+		return x.Quotes
+	}
+	return nil
+}
+
+func (x *BatchQuoteResponse) GetEstimates() []*EstimateResult {
+	if x != nil {
+		return x.Estimates
+	}
+	return nil
+}
+
+type QuoteResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Outcome:
+	//
+	//	*QuoteResult_Result
+	//	*QuoteResult_Error
+	Outcome       isQuoteResult_Outcome `protobuf_oneof:"outcome"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuoteResult) Reset() {
+	*x = QuoteResult{}
+	mi := &file_aggregator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuoteResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteResult) ProtoMessage() {}
+
+func (x *QuoteResult) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteResult.ProtoReflect.Descriptor instead.
+func (*QuoteResult) Descriptor() ([]byte, []int) {
+	return file_aggregator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *QuoteResult) GetOutcome() isQuoteResult_Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return nil
+}
+
+func (x *QuoteResult) GetResult() *QuoteResponse {
+	if x != nil {
+		if x, ok := x.Outcome.(*QuoteResult_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+func (x *QuoteResult) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Outcome.(*QuoteResult_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isQuoteResult_Outcome interface {
+	isQuoteResult_Outcome()
+}
+
+type QuoteResult_Result struct {
+	Result *QuoteResponse `protobuf:"bytes,1,opt,name=result,proto3,oneof"`
+}
+
+type QuoteResult_Error struct {
+	Error *Error `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*QuoteResult_Result) isQuoteResult_Outcome() {}
+
+func (*QuoteResult_Error) isQuoteResult_Outcome() {}
+
+type EstimateResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Outcome:
+	//
+	//	*EstimateResult_Result
+	//	*EstimateResult_Error
+	Outcome       isEstimateResult_Outcome `protobuf_oneof:"outcome"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EstimateResult) Reset() {
+	*x = EstimateResult{}
+	mi := &file_aggregator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EstimateResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EstimateResult) ProtoMessage() {}
+
+func (x *EstimateResult) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EstimateResult.ProtoReflect.Descriptor instead.
+func (*EstimateResult) Descriptor() ([]byte, []int) {
+	return file_aggregator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EstimateResult) GetOutcome() isEstimateResult_Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return nil
+}
+
+func (x *EstimateResult) GetResult() *EstimateResponse {
+	if x != nil {
+		if x, ok := x.Outcome.(*EstimateResult_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+func (x *EstimateResult) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Outcome.(*EstimateResult_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isEstimateResult_Outcome interface {
+	isEstimateResult_Outcome()
+}
+
+type EstimateResult_Result struct {
+	Result *EstimateResponse `protobuf:"bytes,1,opt,name=result,proto3,oneof"`
+}
+
+type EstimateResult_Error struct {
+	Error *Error `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*EstimateResult_Result) isEstimateResult_Outcome() {}
+
+func (*EstimateResult_Error) isEstimateResult_Outcome() {}
+
+type WatchQuoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Quote         *QuoteRequest          `protobuf:"bytes,1,opt,name=quote,proto3" json:"quote,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchQuoteRequest) Reset() {
+	*x = WatchQuoteRequest{}
+	mi := &file_aggregator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchQuoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchQuoteRequest) ProtoMessage() {}
+
+func (x *WatchQuoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchQuoteRequest.ProtoReflect.Descriptor instead.
+func (*WatchQuoteRequest) Descriptor() ([]byte, []int) {
+	return file_aggregator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WatchQuoteRequest) GetQuote() *QuoteRequest {
+	if x != nil {
+		return x.Quote
+	}
+	return nil
+}
+
+type WatchQuoteResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	BlockNumber uint64                 `protobuf:"varint,1,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	// Types that are valid to be assigned to Outcome:
+	//
+	//	*WatchQuoteResponse_Quote
+	//	*WatchQuoteResponse_Error
+	Outcome       isWatchQuoteResponse_Outcome `protobuf_oneof:"outcome"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchQuoteResponse) Reset() {
+	*x = WatchQuoteResponse{}
+	mi := &file_aggregator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchQuoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchQuoteResponse) ProtoMessage() {}
+
+func (x *WatchQuoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_aggregator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchQuoteResponse.ProtoReflect.Descriptor instead.
+func (*WatchQuoteResponse) Descriptor() ([]byte, []int) {
+	return file_aggregator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatchQuoteResponse) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *WatchQuoteResponse) GetOutcome() isWatchQuoteResponse_Outcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return nil
+}
+
+func (x *WatchQuoteResponse) GetQuote() *QuoteResponse {
+	if x != nil {
+		if x, ok := x.Outcome.(*WatchQuoteResponse_Quote); ok {
+			return x.Quote
+		}
+	}
+	return nil
+}
+
+func (x *WatchQuoteResponse) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Outcome.(*WatchQuoteResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isWatchQuoteResponse_Outcome interface {
+	isWatchQuoteResponse_Outcome()
+}
+
+type WatchQuoteResponse_Quote struct {
+	Quote *QuoteResponse `protobuf:"bytes,2,opt,name=quote,proto3,oneof"`
+}
+
+type WatchQuoteResponse_Error struct {
+	Error *Error `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*WatchQuoteResponse_Quote) isWatchQuoteResponse_Outcome() {}
+
+func (*WatchQuoteResponse_Error) isWatchQuoteResponse_Outcome() {}
+
+var File_aggregator_proto protoreflect.FileDescriptor
+
+const file_aggregator_proto_rawDesc = "" +
+	"\n" +
+	"\x10aggregator.proto\x12\raggregator.v1\"J\n" +
+	"\fQuoteRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\tR\x06amount\"\xc5\x02\n" +
+	"\rQuoteResponse\x12\x1d\n" +
+	"\n" +
+	"from_token\x18\x01 \x01(\tR\tfromToken\x12\x19\n" +
+	"\bto_token\x18\x02 \x01(\tR\atoToken\x12\x1f\n" +
+	"\vfrom_amount\x18\x03 \x01(\tR\n" +
+	"fromAmount\x12\x1b\n" +
+	"\tto_amount\x18\x04 \x01(\tR\btoAmount\x126\n" +
+	"\n" +
+	"best_quote\x18\x05 \x01(\v2\x17.aggregator.v1.DEXQuoteR\tbestQuote\x126\n" +
+	"\n" +
+	"all_quotes\x18\x06 \x03(\v2\x17.aggregator.v1.DEXQuoteR\tallQuotes\x12\"\n" +
+	"\rnet_to_amount\x18\a \x01(\tR\vnetToAmount\x12(\n" +
+	"\x03gas\x18\b \x01(\v2\x16.aggregator.v1.GasInfoR\x03gas\"\xfb\x01\n" +
+	"\bDEXQuote\x12\x10\n" +
+	"\x03dex\x18\x01 \x01(\tR\x03dex\x12\x12\n" +
+	"\x04pool\x18\x02 \x01(\tR\x04pool\x12\x1b\n" +
+	"\tto_amount\x18\x03 \x01(\tR\btoAmount\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\tR\x05price\x124\n" +
+	"\tpool_info\x18\x05 \x01(\v2\x17.aggregator.v1.PoolInfoR\bpoolInfo\x12\"\n" +
+	"\rnet_to_amount\x18\x06 \x01(\tR\vnetToAmount\x12!\n" +
+	"\fgas_estimate\x18\a \x01(\x04R\vgasEstimate\x12\x19\n" +
+	"\bgas_cost\x18\b \x01(\tR\agasCost\"\x87\x01\n" +
+	"\aGasInfo\x12\x19\n" +
+	"\bbase_fee\x18\x01 \x01(\tR\abaseFee\x12!\n" +
+	"\fpriority_fee\x18\x02 \x01(\tR\vpriorityFee\x12\x1b\n" +
+	"\tgas_price\x18\x03 \x01(\tR\bgasPrice\x12!\n" +
+	"\fblock_number\x18\x04 \x01(\x04R\vblockNumber\"\x94\x02\n" +
+	"\bPoolInfo\x12\x10\n" +
+	"\x03tvl\x18\x01 \x01(\tR\x03tvl\x12\x1d\n" +
+	"\n" +
+	"tvl_source\x18\x02 \x01(\tR\ttvlSource\x12\x1d\n" +
+	"\n" +
+	"volume_24h\x18\x03 \x01(\tR\tvolume24h\x12\x19\n" +
+	"\bfees_24h\x18\x04 \x01(\tR\afees24h\x12\x1a\n" +
+	"\breserve0\x18\x05 \x01(\tR\breserve0\x12\x1a\n" +
+	"\breserve1\x18\x06 \x01(\tR\breserve1\x12#\n" +
+	"\rtoken0_symbol\x18\a \x01(\tR\ftoken0Symbol\x12#\n" +
+	"\rtoken1_symbol\x18\b \x01(\tR\ftoken1Symbol\x12\x1b\n" +
+	"\tis_active\x18\t \x01(\bR\bisActive\"h\n" +
+	"\x0fEstimateRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x10\n" +
+	"\x03src\x18\x02 \x01(\tR\x03src\x12\x10\n" +
+	"\x03dst\x18\x03 \x01(\tR\x03dst\x12\x1d\n" +
+	"\n" +
+	"src_amount\x18\x04 \x01(\tR\tsrcAmount\"N\n" +
+	"\x10EstimateResponse\x12\x1d\n" +
+	"\n" +
+	"dst_amount\x18\x01 \x01(\tR\tdstAmount\x12\x1b\n" +
+	"\tpool_type\x18\x02 \x01(\tR\bpoolType\"@\n" +
+	"\x05Error\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\tR\terrorCode\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x86\x01\n" +
+	"\x11BatchQuoteRequest\x123\n" +
+	"\x06quotes\x18\x01 \x03(\v2\x1b.aggregator.v1.QuoteRequestR\x06quotes\x12<\n" +
+	"\testimates\x18\x02 \x03(\v2\x1e.aggregator.v1.EstimateRequestR\testimates\"\xa8\x01\n" +
+	"\x12BatchQuoteResponse\x12!\n" +
+	"\fblock_number\x18\x01 \x01(\x04R\vblockNumber\x122\n" +
+	"\x06quotes\x18\x02 \x03(\v2\x1a.aggregator.v1.QuoteResultR\x06quotes\x12;\n" +
+	"\testimates\x18\x03 \x03(\v2\x1d.aggregator.v1.EstimateResultR\testimates\"~\n" +
+	"\vQuoteResult\x126\n" +
+	"\x06result\x18\x01 \x01(\v2\x1c.aggregator.v1.QuoteResponseH\x00R\x06result\x12,\n" +
+	"\x05error\x18\x02 \x01(\v2\x14.aggregator.v1.ErrorH\x00R\x05errorB\t\n" +
+	"\aoutcome\"\x84\x01\n" +
+	"\x0eEstimateResult\x129\n" +
+	"\x06result\x18\x01 \x01(\v2\x1f.aggregator.v1.EstimateResponseH\x00R\x06result\x12,\n" +
+	"\x05error\x18\x02 \x01(\v2\x14.aggregator.v1.ErrorH\x00R\x05errorB\t\n" +
+	"\aoutcome\"F\n" +
+	"\x11WatchQuoteRequest\x121\n" +
+	"\x05quote\x18\x01 \x01(\v2\x1b.aggregator.v1.QuoteRequestR\x05quote\"\xa6\x01\n" +
+	"\x12WatchQuoteResponse\x12!\n" +
+	"\fblock_number\x18\x01 \x01(\x04R\vblockNumber\x124\n" +
+	"\x05quote\x18\x02 \x01(\v2\x1c.aggregator.v1.QuoteResponseH\x00R\x05quote\x12,\n" +
+	"\x05error\x18\x03 \x01(\v2\x14.aggregator.v1.ErrorH\x00R\x05errorB\t\n" +
+	"\aoutcome2\xcc\x02\n" +
+	"\x11AggregatorService\x12B\n" +
+	"\x05Quote\x12\x1b.aggregator.v1.QuoteRequest\x1a\x1c.aggregator.v1.QuoteResponse\x12K\n" +
+	"\bEstimate\x12\x1e.aggregator.v1.EstimateRequest\x1a\x1f.aggregator.v1.EstimateResponse\x12Q\n" +
+	"\n" +
+	"BatchQuote\x12 .aggregator.v1.BatchQuoteRequest\x1a!.aggregator.v1.BatchQuoteResponse\x12S\n" +
+	"\n" +
+	"WatchQuote\x12 .aggregator.v1.WatchQuoteRequest\x1a!.aggregator.v1.WatchQuoteResponse0\x01BHZFgithub.com/DiDinar5/mini-dex-aggregator/api/aggregator/v1;aggregatorv1b\x06proto3"
+
+var (
+	file_aggregator_proto_rawDescOnce sync.Once
+	file_aggregator_proto_rawDescData []byte
+)
+
+func file_aggregator_proto_rawDescGZIP() []byte {
+	file_aggregator_proto_rawDescOnce.Do(func() {
+		file_aggregator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_aggregator_proto_rawDesc), len(file_aggregator_proto_rawDesc)))
+	})
+	return file_aggregator_proto_rawDescData
+}
+
+var file_aggregator_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_aggregator_proto_goTypes = []any{
+	(*QuoteRequest)(nil),       // 0: aggregator.v1.QuoteRequest
+	(*QuoteResponse)(nil),      // 1: aggregator.v1.QuoteResponse
+	(*DEXQuote)(nil),           // 2: aggregator.v1.DEXQuote
+	(*GasInfo)(nil),            // 3: aggregator.v1.GasInfo
+	(*PoolInfo)(nil),           // 4: aggregator.v1.PoolInfo
+	(*EstimateRequest)(nil),    // 5: aggregator.v1.EstimateRequest
+	(*EstimateResponse)(nil),   // 6: aggregator.v1.EstimateResponse
+	(*Error)(nil),              // 7: aggregator.v1.Error
+	(*BatchQuoteRequest)(nil),  // 8: aggregator.v1.BatchQuoteRequest
+	(*BatchQuoteResponse)(nil), // 9: aggregator.v1.BatchQuoteResponse
+	(*QuoteResult)(nil),        // 10: aggregator.v1.QuoteResult
+	(*EstimateResult)(nil),     // 11: aggregator.v1.EstimateResult
+	(*WatchQuoteRequest)(nil),  // 12: aggregator.v1.WatchQuoteRequest
+	(*WatchQuoteResponse)(nil), // 13: aggregator.v1.WatchQuoteResponse
+}
+var file_aggregator_proto_depIdxs = []int32{
+	2,  // 0: aggregator.v1.QuoteResponse.best_quote:type_name -> aggregator.v1.DEXQuote
+	2,  // 1: aggregator.v1.QuoteResponse.all_quotes:type_name -> aggregator.v1.DEXQuote
+	3,  // 2: aggregator.v1.QuoteResponse.gas:type_name -> aggregator.v1.GasInfo
+	4,  // 3: aggregator.v1.DEXQuote.pool_info:type_name -> aggregator.v1.PoolInfo
+	0,  // 4: aggregator.v1.BatchQuoteRequest.quotes:type_name -> aggregator.v1.QuoteRequest
+	5,  // 5: aggregator.v1.BatchQuoteRequest.estimates:type_name -> aggregator.v1.EstimateRequest
+	10, // 6: aggregator.v1.BatchQuoteResponse.quotes:type_name -> aggregator.v1.QuoteResult
+	11, // 7: aggregator.v1.BatchQuoteResponse.estimates:type_name -> aggregator.v1.EstimateResult
+	1,  // 8: aggregator.v1.QuoteResult.result:type_name -> aggregator.v1.QuoteResponse
+	7,  // 9: aggregator.v1.QuoteResult.error:type_name -> aggregator.v1.Error
+	6,  // 10: aggregator.v1.EstimateResult.result:type_name -> aggregator.v1.EstimateResponse
+	7,  // 11: aggregator.v1.EstimateResult.error:type_name -> aggregator.v1.Error
+	0,  // 12: aggregator.v1.WatchQuoteRequest.quote:type_name -> aggregator.v1.QuoteRequest
+	1,  // 13: aggregator.v1.WatchQuoteResponse.quote:type_name -> aggregator.v1.QuoteResponse
+	7,  // 14: aggregator.v1.WatchQuoteResponse.error:type_name -> aggregator.v1.Error
+	0,  // 15: aggregator.v1.AggregatorService.Quote:input_type -> aggregator.v1.QuoteRequest
+	5,  // 16: aggregator.v1.AggregatorService.Estimate:input_type -> aggregator.v1.EstimateRequest
+	8,  // 17: aggregator.v1.AggregatorService.BatchQuote:input_type -> aggregator.v1.BatchQuoteRequest
+	12, // 18: aggregator.v1.AggregatorService.WatchQuote:input_type -> aggregator.v1.WatchQuoteRequest
+	1,  // 19: aggregator.v1.AggregatorService.Quote:output_type -> aggregator.v1.QuoteResponse
+	6,  // 20: aggregator.v1.AggregatorService.Estimate:output_type -> aggregator.v1.EstimateResponse
+	9,  // 21: aggregator.v1.AggregatorService.BatchQuote:output_type -> aggregator.v1.BatchQuoteResponse
+	13, // 22: aggregator.v1.AggregatorService.WatchQuote:output_type -> aggregator.v1.WatchQuoteResponse
+	19, // [19:23] is the sub-list for method output_type
+	15, // [15:19] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_aggregator_proto_init() }
+func file_aggregator_proto_init() {
+	if File_aggregator_proto != nil {
+		return
+	}
+	file_aggregator_proto_msgTypes[10].OneofWrappers = []any{
+		(*QuoteResult_Result)(nil),
+		(*QuoteResult_Error)(nil),
+	}
+	file_aggregator_proto_msgTypes[11].OneofWrappers = []any{
+		(*EstimateResult_Result)(nil),
+		(*EstimateResult_Error)(nil),
+	}
+	file_aggregator_proto_msgTypes[13].OneofWrappers = []any{
+		(*WatchQuoteResponse_Quote)(nil),
+		(*WatchQuoteResponse_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_aggregator_proto_rawDesc), len(file_aggregator_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_aggregator_proto_goTypes,
+		DependencyIndexes: file_aggregator_proto_depIdxs,
+		MessageInfos:      file_aggregator_proto_msgTypes,
+	}.Build()
+	File_aggregator_proto = out.File
+	file_aggregator_proto_goTypes = nil
+	file_aggregator_proto_depIdxs = nil
+}
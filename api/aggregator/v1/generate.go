@@ -0,0 +1,5 @@
+// Package aggregatorv1 holds the protobuf definition of the gRPC API and
+// the code generated from it.
+package aggregatorv1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative aggregator.proto
@@ -0,0 +1,259 @@
+// Package client calls the aggregator's REST API with typed requests and
+// results. Failed calls return an *APIError carrying the server's error
+// code, and integer amounts are returned as *big.Int.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option configures a Client built by New.
+type Option func(*Client)
+
+// WithAPIKey sends key in the X-API-Key header of every request.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithHTTPClient sends requests through httpClient instead of a default
+// http.Client.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout bounds each attempt of a call; retries get a fresh timeout.
+// Zero leaves attempts bounded by the caller's context only. The default is
+// 10 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetries sets how many additional attempts are made after a 429, a
+// 502, 503 or 504, or a transport error, and the jittered exponential
+// backoff between them, which never exceeds maxBackoff. A Retry-After
+// header longer than the backoff is honoured up to maxBackoff; a longer one,
+// e.g. from an exhausted daily quota, ends the call with its *APIError. The
+// default is 2 retries from 200ms up to 2s.
+func WithRetries(maxRetries int, backoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// Client calls one aggregator server. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	timeout    time.Duration
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+// New returns a client for the server at baseURL, e.g.
+// "http://localhost:1337".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{},
+		timeout:    10 * time.Second,
+		maxRetries: 2,
+		backoff:    200 * time.Millisecond,
+		maxBackoff: 2 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// request describes one API call. Statuses other than 200 and those in
+// accept are decoded as an ErrorResponse.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+	accept []int
+}
+
+// do performs req, retrying transient failures, and decodes the response
+// body into out. Every endpoint is read-only, so any call may be retried.
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	var payload []byte
+	if req.body != nil {
+		var err error
+		if payload, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("failed to encode %s request: %w", req.path, err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := c.attempt(ctx, req, payload, out)
+		if err == nil || ctx.Err() != nil || !retryable(err) || attempt >= c.maxRetries {
+			return err
+		}
+		delay, ok := c.retryDelay(attempt, err)
+		if !ok {
+			return err
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, req request, payload []byte, out any) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	httpReq, err := c.newRequest(ctx, req.method, req.path, req.query, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return &transportError{err: err}
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && !accepted(res.StatusCode, req.accept) {
+		return decodeError(res)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", req.path, err)
+	}
+	return nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	return req, nil
+}
+
+func accepted(status int, accept []int) bool {
+	for _, code := range accept {
+		if status == code {
+			return true
+		}
+	}
+	return false
+}
+
+// transportError is a request that got no response, e.g. a refused
+// connection or an attempt that timed out.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+// retryable reports whether err is worth another attempt: throttling,
+// gateway failures and transport errors are. Everything the server rejected
+// on its merits is not, nor is a response that failed to decode, which
+// would fail the same way again.
+func retryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var transportErr *transportError
+	return errors.As(err, &transportErr)
+}
+
+// retryDelay returns a full-jitter exponential delay for the given attempt,
+// honouring a server supplied Retry-After when it is longer. It reports
+// false when the server asks for a longer wait than maxBackoff allows.
+func (c *Client) retryDelay(attempt int, err error) (time.Duration, bool) {
+	base := c.backoff
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	limit := c.maxBackoff
+	if limit <= 0 {
+		limit = base
+	}
+
+	ceiling := base << attempt
+	if ceiling > limit || ceiling <= 0 {
+		ceiling = limit
+	}
+
+	delay := time.Duration(rand.Int63n(int64(ceiling) + 1))
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+		if apiErr.RetryAfter > limit {
+			return 0, false
+		}
+		delay = apiErr.RetryAfter
+	}
+
+	return delay, true
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
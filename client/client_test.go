@@ -0,0 +1,354 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/internal/handler"
+	"github.com/DiDinar5/mini-dex-aggregator/internal/middlewares/validator"
+	"github.com/labstack/echo/v4"
+)
+
+// largeAmount does not fit in 64 bits, so it only survives decoding as a
+// big.Int.
+const largeAmount = "123456789012345678901234567890"
+
+// stubUsecase quotes WETH/USDC and estimates any pool; every other pair has
+// no liquidity.
+type stubUsecase struct{}
+
+func (stubUsecase) Quote(_ context.Context, req domain.QuoteRequest) (domain.QuoteResponse, error) {
+	if req.From != "WETH" || req.To != "USDC" {
+		return domain.QuoteResponse{}, domain.NewError(domain.CodeNoLiquidity, "no pools found", nil)
+	}
+	best := domain.DEXQuote{DEX: "UniswapV2", Pool: "0xpool", ToAmount: largeAmount, NetToAmount: largeAmount, GasEstimate: 120000, GasCost: "2400000000000000"}
+	return domain.QuoteResponse{
+		FromToken:   req.From,
+		ToToken:     req.To,
+		FromAmount:  req.Amount,
+		ToAmount:    largeAmount,
+		NetToAmount: largeAmount,
+		BestQuote:   best,
+		AllQuotes:   []domain.DEXQuote{best, {DEX: "Sushiswap", Pool: "0xother", ToAmount: "7"}},
+		Gas:         &domain.GasInfo{BaseFee: "19000000000", PriorityFee: "1000000000", GasPrice: "20000000000", BlockNumber: 42},
+	}, nil
+}
+
+func (stubUsecase) Estimate(context.Context, domain.EstimateRequest) (domain.EstimateResponse, error) {
+	return domain.EstimateResponse{DstAmount: largeAmount, PoolType: domain.PoolTypeUniswapV2}, nil
+}
+
+func (u stubUsecase) BatchQuote(ctx context.Context, req domain.BatchRequest) (domain.BatchResponse, error) {
+	response := domain.BatchResponse{BlockNumber: 42}
+	for _, quoteReq := range req.Quotes {
+		quote, err := u.Quote(ctx, quoteReq)
+		if err != nil {
+			errResponse := domain.NewErrorResponse(err)
+			response.Quotes = append(response.Quotes, domain.BatchQuoteResult{Error: &errResponse})
+			continue
+		}
+		response.Quotes = append(response.Quotes, domain.BatchQuoteResult{Result: &quote})
+	}
+	return response, nil
+}
+
+func (stubUsecase) Pools(context.Context, domain.PoolsRequest) (domain.PoolsResponse, error) {
+	return domain.PoolsResponse{}, nil
+}
+
+func (stubUsecase) PoolDetail(context.Context, domain.PoolDetailRequest) (domain.PoolDetail, error) {
+	return domain.PoolDetail{}, domain.NewError(domain.CodePoolNotFound, "pool not found", nil)
+}
+
+func (stubUsecase) Tokens(context.Context, domain.TokensRequest) (domain.TokensResponse, error) {
+	return domain.TokensResponse{Tokens: []domain.Token{{Symbol: "WETH", Decimals: 18}}, Count: 1}, nil
+}
+
+func (stubUsecase) PriceTick(_ context.Context, req domain.PriceTickRequest) (domain.PriceTick, error) {
+	return domain.PriceTick{FromToken: req.From, ToToken: req.To, Price: "2000.5", BlockNumber: 42}, nil
+}
+
+func (stubUsecase) Price(context.Context, domain.PriceRequest) (domain.PriceResponse, error) {
+	return domain.PriceResponse{}, nil
+}
+
+type stubReadiness struct{}
+
+func (stubReadiness) Readiness(context.Context) domain.ReadinessResponse {
+	return domain.ReadinessResponse{
+		Status: domain.HealthFailing,
+		Checks: []domain.DependencyCheck{{Name: "rpc", Status: domain.HealthFailing}},
+	}
+}
+
+type stubGas struct{}
+
+func (stubGas) GasSuggestion(context.Context) (*domain.GasSuggestion, error) {
+	tier := domain.GasTier{Percentile: 50, MaxFeePerGas: big.NewInt(40), MaxPriorityFeePerGas: big.NewInt(2)}
+	return &domain.GasSuggestion{BlockNumber: 42, BlockCount: 20, BaseFee: big.NewInt(19), Slow: tier, Standard: tier, Fast: tier}, nil
+}
+
+type stubBlocks struct{}
+
+func (stubBlocks) Subscribe() (<-chan uint64, func()) { return make(chan uint64), func() {} }
+func (stubBlocks) Latest() uint64                     { return 42 }
+
+// newServer serves the real Handler over stubs. wrap, when given, sits in
+// front of it to inject failures.
+func newServer(t *testing.T, wrap func(http.Handler) http.Handler) *httptest.Server {
+	t.Helper()
+
+	h := handler.NewHandler(stubUsecase{}, nil, nil, stubReadiness{}, stubGas{}, http.NotFoundHandler(), stubBlocks{}, 0)
+	e := echo.New()
+	e.Validator = validator.NewValidator()
+	e.HTTPErrorHandler = handler.HTTPErrorHandler
+	h.SetupRoutes(e)
+
+	var served http.Handler = e
+	if wrap != nil {
+		served = wrap(e)
+	}
+	srv := httptest.NewServer(served)
+	t.Cleanup(func() {
+		h.CloseStreams()
+		srv.Close()
+	})
+	return srv
+}
+
+func TestQuoteDecodesAmounts(t *testing.T) {
+	c := New(newServer(t, nil).URL)
+
+	quote, err := c.Quote(context.Background(), "WETH", "USDC", big.NewInt(1))
+	if err != nil {
+		t.Fatalf("Quote: %v", err)
+	}
+
+	if quote.ToAmount.String() != largeAmount || quote.Best.ToAmount.String() != largeAmount {
+		t.Errorf("amounts = %s, %s, want %s", quote.ToAmount, quote.Best.ToAmount, largeAmount)
+	}
+	if len(quote.All) != 2 || quote.All[1].ToAmount.Int64() != 7 {
+		t.Errorf("all quotes = %+v", quote.All)
+	}
+	if quote.NetToAmount.String() != largeAmount || quote.Best.GasCost.String() != "2400000000000000" || quote.Gas.GasPrice != "20000000000" {
+		t.Errorf("gas = %s, %s, %+v", quote.NetToAmount, quote.Best.GasCost, quote.Gas)
+	}
+	if quote.All[1].NetToAmount != nil || quote.All[1].GasCost != nil {
+		t.Errorf("omitted net fields decoded as %s, %s", quote.All[1].NetToAmount, quote.All[1].GasCost)
+	}
+}
+
+func TestEstimateDecodesAmount(t *testing.T) {
+	c := New(newServer(t, nil).URL)
+
+	estimate, err := c.Estimate(context.Background(), "0xpool", "0xa", "0xb", big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("Estimate: %v", err)
+	}
+	if estimate.DstAmount.String() != largeAmount || estimate.PoolType != domain.PoolTypeUniswapV2 {
+		t.Errorf("estimate = %+v", estimate)
+	}
+}
+
+func TestErrorResponsesDecodeToAPIError(t *testing.T) {
+	var calls atomic.Int32
+	srv := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			next.ServeHTTP(w, r)
+		})
+	})
+	c := New(srv.URL)
+
+	_, err := c.Quote(context.Background(), "WETH", "PEPE", big.NewInt(1))
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Code != domain.CodeNoLiquidity || apiErr.Message != "no pools found" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+	if !errors.Is(err, domain.ErrNoLiquidity) {
+		t.Error("errors.Is(err, domain.ErrNoLiquidity) = false")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1: client errors must not be retried", calls.Load())
+	}
+
+	_, err = c.PoolDetail(context.Background(), "0xmissing")
+	if !errors.Is(err, domain.ErrPoolNotFound) {
+		t.Errorf("PoolDetail error = %v, want pool not found", err)
+	}
+
+	// Validation failures come from the handler, not the usecase.
+	_, err = c.Quote(context.Background(), "", "USDC", big.NewInt(1))
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("error = %v, want invalid input", err)
+	}
+}
+
+func TestRetriesTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	srv := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	c := New(srv.URL, WithRetries(2, time.Millisecond, time.Millisecond))
+	if _, err := c.Tokens(context.Background()); err != nil {
+		t.Fatalf("Tokens after two 503s: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3", calls.Load())
+	}
+
+	calls.Store(0)
+	c = New(srv.URL, WithRetries(1, time.Millisecond, time.Millisecond))
+	_, err := c.Tokens(context.Background())
+	if !errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Errorf("error = %v, want upstream unavailable once retries run out", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2", calls.Load())
+	}
+}
+
+func TestUndecodableResponseIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Write([]byte("<html>maintenance</html>"))
+		})
+	})
+
+	c := New(srv.URL, WithRetries(2, time.Millisecond, time.Millisecond))
+	if _, err := c.Tokens(context.Background()); err == nil {
+		t.Fatal("Tokens decoded an HTML body")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1: a body that fails to decode fails again", calls.Load())
+	}
+}
+
+func TestLongRetryAfterIsNotWaitedOut(t *testing.T) {
+	var calls atomic.Int32
+	srv := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Retry-After", "36000")
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+	})
+
+	start := time.Now()
+	_, err := New(srv.URL).Tokens(context.Background())
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.RetryAfter != 10*time.Hour {
+		t.Fatalf("error = %v, want the 429 with its Retry-After", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %s, want no wait", elapsed)
+	}
+}
+
+func TestTimeoutAppliesPerAttempt(t *testing.T) {
+	srv := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+		})
+	})
+
+	c := New(srv.URL, WithTimeout(20*time.Millisecond), WithRetries(0, 0, 0))
+	_, err := c.Tokens(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestBatchQuoteKeepsItemErrors(t *testing.T) {
+	c := New(newServer(t, nil).URL)
+
+	batch, err := c.BatchQuote(context.Background(), domain.BatchRequest{Quotes: []domain.QuoteRequest{
+		{From: "WETH", To: "USDC", Amount: "1"},
+		{From: "WETH", To: "PEPE", Amount: "1"},
+	}})
+	if err != nil {
+		t.Fatalf("BatchQuote: %v", err)
+	}
+
+	if batch.BlockNumber != 42 || len(batch.Quotes) != 2 {
+		t.Fatalf("batch = %+v", batch)
+	}
+	if batch.Quotes[0].Err != nil || batch.Quotes[0].Quote.ToAmount.String() != largeAmount {
+		t.Errorf("first item = %+v", batch.Quotes[0])
+	}
+	if !errors.Is(batch.Quotes[1].Err, domain.ErrNoLiquidity) {
+		t.Errorf("second item error = %v, want no liquidity", batch.Quotes[1].Err)
+	}
+}
+
+func TestGas(t *testing.T) {
+	c := New(newServer(t, nil).URL)
+
+	gas, err := c.Gas(context.Background())
+	if err != nil {
+		t.Fatalf("Gas: %v", err)
+	}
+	if gas.BlockNumber != 42 || gas.BaseFee != "19" || gas.Standard.MaxFeePerGas != "40" || gas.Fast.MaxPriorityFeePerGas != "2" {
+		t.Errorf("gas = %+v", gas)
+	}
+}
+
+func TestReadinessReportsNotReady(t *testing.T) {
+	c := New(newServer(t, nil).URL)
+
+	readiness, err := c.Readiness(context.Background())
+	if err != nil {
+		t.Fatalf("Readiness: %v", err)
+	}
+	if readiness.Ready() || len(readiness.Checks) != 1 {
+		t.Errorf("readiness = %+v", readiness)
+	}
+}
+
+func TestWatchPrice(t *testing.T) {
+	c := New(newServer(t, nil).URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := c.WatchPrice(ctx, "WETH", "USDC")
+	if err != nil {
+		t.Fatalf("WatchPrice: %v", err)
+	}
+
+	select {
+	case event := <-events:
+		if event.Err != nil || event.BlockNumber != 42 || event.Tick.Price != "2000.5" {
+			t.Errorf("event = %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no price event")
+	}
+}
@@ -0,0 +1,315 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+// Quote is a QuoteResponse with its amounts parsed. Amounts are whole token
+// units, as the API reports them. NetToAmount and Gas are nil when the
+// server could not price gas.
+type Quote struct {
+	FromToken   string
+	ToToken     string
+	FromAmount  *big.Int
+	ToAmount    *big.Int
+	NetToAmount *big.Int
+	Best        DEXQuote
+	All         []DEXQuote
+	Gas         *domain.GasInfo
+}
+
+// DEXQuote is what one pool pays out for the quoted amount, gross and net
+// of gas. GasCost is in wei.
+type DEXQuote struct {
+	DEX         string
+	Pool        string
+	ToAmount    *big.Int
+	NetToAmount *big.Int
+	GasEstimate uint64
+	GasCost     *big.Int
+	PoolInfo    *domain.PoolInfo
+}
+
+// Estimate is an EstimateResponse with DstAmount in base units of the dst
+// token.
+type Estimate struct {
+	DstAmount *big.Int
+	PoolType  domain.PoolType
+}
+
+// Batch holds one result per requested item, in request order, all
+// evaluated at BlockNumber.
+type Batch struct {
+	BlockNumber uint64
+	Quotes      []QuoteResult
+	Estimates   []EstimateResult
+}
+
+// QuoteResult is either a quote or the error that item failed with.
+type QuoteResult struct {
+	Quote *Quote
+	Err   error
+}
+
+// EstimateResult is either an estimate or the error that item failed with.
+type EstimateResult struct {
+	Estimate *Estimate
+	Err      error
+}
+
+// Quote quotes amount whole units of from into to; tokens are registry
+// symbols.
+func (c *Client) Quote(ctx context.Context, from, to string, amount *big.Int) (*Quote, error) {
+	var response domain.QuoteResponse
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/quote",
+		query:  url.Values{"from": {from}, "to": {to}, "amount": {amount.String()}},
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+	return quoteFromResponse(&response)
+}
+
+// Estimate computes what swapping srcAmount base units of src through the
+// Uniswap V2 pool pays out in dst; tokens are addresses.
+func (c *Client) Estimate(ctx context.Context, pool, src, dst string, srcAmount *big.Int) (*Estimate, error) {
+	var response domain.EstimateResponse
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/estimate",
+		query:  url.Values{"pool": {pool}, "src": {src}, "dst": {dst}, "src_amount": {srcAmount.String()}},
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+	return estimateFromResponse(&response)
+}
+
+// BatchQuote evaluates every item against one block. A failed item only
+// sets the Err of its own result.
+func (c *Client) BatchQuote(ctx context.Context, req domain.BatchRequest) (*Batch, error) {
+	var response domain.BatchResponse
+	err := c.do(ctx, request{method: http.MethodPost, path: "/quote/batch", body: req}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &Batch{
+		BlockNumber: response.BlockNumber,
+		Quotes:      make([]QuoteResult, len(response.Quotes)),
+		Estimates:   make([]EstimateResult, len(response.Estimates)),
+	}
+	for i, item := range response.Quotes {
+		switch {
+		case item.Error != nil:
+			batch.Quotes[i].Err = errorFromResponse(item.Error)
+		case item.Result != nil:
+			batch.Quotes[i].Quote, batch.Quotes[i].Err = quoteFromResponse(item.Result)
+		}
+	}
+	for i, item := range response.Estimates {
+		switch {
+		case item.Error != nil:
+			batch.Estimates[i].Err = errorFromResponse(item.Error)
+		case item.Result != nil:
+			batch.Estimates[i].Estimate, batch.Estimates[i].Err = estimateFromResponse(item.Result)
+		}
+	}
+
+	return batch, nil
+}
+
+// Price reports the spot price of one whole base in quote.
+func (c *Client) Price(ctx context.Context, base, quote string) (*domain.PriceResponse, error) {
+	var response domain.PriceResponse
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/price",
+		query:  url.Values{"base": {base}, "quote": {quote}},
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Pools lists the crawled pools matching req.
+func (c *Client) Pools(ctx context.Context, req domain.PoolsRequest) (*domain.PoolsResponse, error) {
+	query := url.Values{}
+	if req.Token != "" {
+		query.Set("token", req.Token)
+	}
+	if req.DEX != "" {
+		query.Set("dex", req.DEX)
+	}
+
+	var response domain.PoolsResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/pools", query: query}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// PoolDetail reports one pool's on-chain state and subgraph data.
+func (c *Client) PoolDetail(ctx context.Context, address string) (*domain.PoolDetail, error) {
+	var response domain.PoolDetail
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/pools/" + url.PathEscape(address)}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Tokens lists the symbols the server accepts.
+func (c *Client) Tokens(ctx context.Context) (*domain.TokensResponse, error) {
+	var response domain.TokensResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/tokens"}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Gas suggests slow, standard and fast EIP-1559 fees at the chain head.
+func (c *Client) Gas(ctx context.Context) (*domain.GasResponse, error) {
+	var response domain.GasResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/gas"}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CacheStats reports the server's subgraph cache counters.
+func (c *Client) CacheStats(ctx context.Context) (*domain.CacheStats, error) {
+	var response domain.CacheStats
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/cache/stats"}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Usage reports the counters of the client's API key.
+func (c *Client) Usage(ctx context.Context) (*domain.APIKeyUsage, error) {
+	var response domain.APIKeyUsage
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/usage"}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Health reports whether the server process is up.
+func (c *Client) Health(ctx context.Context) (*domain.HealthResponse, error) {
+	var response domain.HealthResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/healthz"}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Readiness reports the server's dependency checks. A server that is not
+// ready is not an error; check Ready on the result.
+func (c *Client) Readiness(ctx context.Context) (*domain.ReadinessResponse, error) {
+	var response domain.ReadinessResponse
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/readyz",
+		accept: []int{http.StatusServiceUnavailable},
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func quoteFromResponse(response *domain.QuoteResponse) (*Quote, error) {
+	fromAmount, err := parseAmount("from_amount", response.FromAmount)
+	if err != nil {
+		return nil, err
+	}
+	toAmount, err := parseAmount("to_amount", response.ToAmount)
+	if err != nil {
+		return nil, err
+	}
+	netToAmount, err := parseOptionalAmount("net_to_amount", response.NetToAmount)
+	if err != nil {
+		return nil, err
+	}
+	best, err := dexQuoteFromResponse(response.BestQuote)
+	if err != nil {
+		return nil, err
+	}
+
+	all := make([]DEXQuote, 0, len(response.AllQuotes))
+	for _, dexQuote := range response.AllQuotes {
+		quote, err := dexQuoteFromResponse(dexQuote)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, quote)
+	}
+
+	return &Quote{
+		FromToken:   response.FromToken,
+		ToToken:     response.ToToken,
+		FromAmount:  fromAmount,
+		ToAmount:    toAmount,
+		NetToAmount: netToAmount,
+		Best:        best,
+		All:         all,
+		Gas:         response.Gas,
+	}, nil
+}
+
+func dexQuoteFromResponse(response domain.DEXQuote) (DEXQuote, error) {
+	toAmount, err := parseAmount("to_amount", response.ToAmount)
+	if err != nil {
+		return DEXQuote{}, err
+	}
+	netToAmount, err := parseOptionalAmount("net_to_amount", response.NetToAmount)
+	if err != nil {
+		return DEXQuote{}, err
+	}
+	gasCost, err := parseOptionalAmount("gas_cost", response.GasCost)
+	if err != nil {
+		return DEXQuote{}, err
+	}
+	return DEXQuote{
+		DEX:         response.DEX,
+		Pool:        response.Pool,
+		ToAmount:    toAmount,
+		NetToAmount: netToAmount,
+		GasEstimate: response.GasEstimate,
+		GasCost:     gasCost,
+		PoolInfo:    response.PoolInfo,
+	}, nil
+}
+
+func estimateFromResponse(response *domain.EstimateResponse) (*Estimate, error) {
+	dstAmount, err := parseAmount("dst_amount", response.DstAmount)
+	if err != nil {
+		return nil, err
+	}
+	return &Estimate{DstAmount: dstAmount, PoolType: response.PoolType}, nil
+}
+
+func parseAmount(field, value string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s in response: %q", field, value)
+	}
+	return amount, nil
+}
+
+// parseOptionalAmount is parseAmount for fields the server may omit.
+func parseOptionalAmount(field, value string) (*big.Int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	return parseAmount(field, value)
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+// APIError is a failed call as the server described it. It unwraps to a
+// *domain.Error of the same code, so errors.Is(err, domain.ErrNoLiquidity)
+// and similar checks work on client errors too.
+type APIError struct {
+	StatusCode int
+	Code       domain.ErrorCode
+	Message    string
+	// RetryAfter is the server's requested delay before trying again, set
+	// on throttled calls.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s (HTTP %d)", e.Code, e.StatusCode)
+	}
+	return fmt.Sprintf("%s: %s (HTTP %d)", e.Code, e.Message, e.StatusCode)
+}
+
+func (e *APIError) Unwrap() error {
+	return domain.NewError(e.Code, e.Message, nil)
+}
+
+// decodeError turns an ErrorResponse body into an *APIError. Bodies that
+// are not one, e.g. from a proxy in front of the server, are classified by
+// their status alone.
+func decodeError(res *http.Response) error {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		RetryAfter: parseRetryAfter(res.Header.Get("Retry-After")),
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+	var response domain.ErrorResponse
+	if err := json.Unmarshal(body, &response); err == nil && response.ErrorCode != "" {
+		apiErr.Code = response.ErrorCode
+		apiErr.Message = response.Description
+		return apiErr
+	}
+
+	apiErr.Code = codeForStatus(res.StatusCode)
+	apiErr.Message = http.StatusText(res.StatusCode)
+	return apiErr
+}
+
+// codeForStatus inverts domain.ErrorCode.HTTPStatus for responses without
+// an error code.
+func codeForStatus(status int) domain.ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return domain.CodeInvalidInput
+	case http.StatusNotFound:
+		return domain.CodeNotFound
+	case http.StatusUnprocessableEntity:
+		return domain.CodeNoLiquidity
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return domain.CodeUpstreamUnavailable
+	case http.StatusGatewayTimeout:
+		return domain.CodeTimeout
+	case http.StatusUnauthorized:
+		return domain.CodeUnauthorized
+	case http.StatusTooManyRequests:
+		return domain.CodeRateLimited
+	default:
+		return domain.CodeInternal
+	}
+}
+
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// errorFromResponse converts an ErrorResponse embedded in a batch result or
+// stream event.
+func errorFromResponse(response *domain.ErrorResponse) *APIError {
+	return &APIError{
+		StatusCode: response.Code,
+		Code:       response.ErrorCode,
+		Message:    response.Description,
+	}
+}
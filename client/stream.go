@@ -0,0 +1,173 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/gorilla/websocket"
+)
+
+// PriceEvent is one block on a price stream: the tick, or the error the
+// server reported for that block.
+type PriceEvent struct {
+	BlockNumber uint64
+	Tick        *domain.PriceTick
+	Err         error
+}
+
+// QuoteEvent is one block on a quote stream: the quote, or the error the
+// server reported for that block.
+type QuoteEvent struct {
+	BlockNumber uint64
+	Quote       *Quote
+	Err         error
+}
+
+// WatchPrice follows the server-sent price stream of from in to, with one
+// event per new block. The channel is closed once ctx is done or the server
+// ends the stream.
+func (c *Client) WatchPrice(ctx context.Context, from, to string) (<-chan PriceEvent, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/stream/price", url.Values{"from": {from}, "to": {to}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, decodeError(res)
+	}
+
+	events := make(chan PriceEvent, 1)
+	go func() {
+		defer res.Body.Close()
+		defer close(events)
+
+		var id uint64
+		var event, data string
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line != "" {
+				field, value, _ := strings.Cut(line, ": ")
+				switch field {
+				case "id":
+					id, _ = strconv.ParseUint(value, 10, 64)
+				case "event":
+					event = value
+				case "data":
+					data = value
+				}
+				continue
+			}
+
+			// A blank line dispatches the event; heartbeats carry no data.
+			if data == "" {
+				continue
+			}
+			select {
+			case events <- priceEvent(id, event, data):
+			case <-ctx.Done():
+				return
+			}
+			event, data = "", ""
+		}
+	}()
+
+	return events, nil
+}
+
+func priceEvent(id uint64, event, data string) PriceEvent {
+	if event == "error" {
+		var response domain.ErrorResponse
+		if err := json.Unmarshal([]byte(data), &response); err != nil {
+			return PriceEvent{BlockNumber: id, Err: fmt.Errorf("failed to decode stream error: %w", err)}
+		}
+		return PriceEvent{BlockNumber: id, Err: errorFromResponse(&response)}
+	}
+
+	var tick domain.PriceTick
+	if err := json.Unmarshal([]byte(data), &tick); err != nil {
+		return PriceEvent{BlockNumber: id, Err: fmt.Errorf("failed to decode price tick: %w", err)}
+	}
+	return PriceEvent{BlockNumber: id, Tick: &tick}
+}
+
+// WatchQuote subscribes to quotes for amount whole units of from into to
+// over the server's WebSocket, with one event per new block. The channel is
+// closed once ctx is done or the connection ends.
+func (c *Client) WatchQuote(ctx context.Context, from, to string, amount *big.Int) (<-chan QuoteEvent, error) {
+	target := "ws" + strings.TrimPrefix(c.baseURL, "http") + "/ws/quote"
+	header := http.Header{}
+	if c.apiKey != "" {
+		header.Set("X-API-Key", c.apiKey)
+	}
+
+	conn, res, err := websocket.DefaultDialer.DialContext(ctx, target, header)
+	if err != nil {
+		if res != nil && res.StatusCode != http.StatusSwitchingProtocols {
+			defer res.Body.Close()
+			return nil, decodeError(res)
+		}
+		return nil, err
+	}
+
+	const subscriptionID = "quote"
+	err = conn.WriteJSON(domain.StreamRequest{
+		Type:   domain.StreamSubscribe,
+		ID:     subscriptionID,
+		From:   from,
+		To:     to,
+		Amount: amount.String(),
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	events := make(chan QuoteEvent, 1)
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	go func() {
+		defer stop()
+		defer conn.Close()
+		defer close(events)
+
+		for {
+			var msg domain.StreamMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+
+			var event QuoteEvent
+			switch {
+			case msg.Type == domain.StreamError && msg.Error != nil:
+				event = QuoteEvent{BlockNumber: msg.BlockNumber, Err: errorFromResponse(msg.Error)}
+			case msg.Type == domain.StreamQuote && msg.Quote != nil:
+				quote, err := quoteFromResponse(msg.Quote)
+				event = QuoteEvent{BlockNumber: msg.BlockNumber, Quote: quote, Err: err}
+			default:
+				continue
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
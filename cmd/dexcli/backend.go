@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/aggregator"
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/thegraph"
+)
+
+// backend is what the commands need from the aggregator, whether it is
+// reached over HTTP or runs in-process.
+type backend interface {
+	// SwapQuote quotes amountIn base units of from into to.
+	SwapQuote(ctx context.Context, from, to domain.Token, amountIn *big.Int) (swapQuote, error)
+	Estimate(ctx context.Context, req domain.EstimateRequest) (domain.EstimateResponse, error)
+	Price(ctx context.Context, req domain.PriceRequest) (domain.PriceResponse, error)
+	Pools(ctx context.Context, req domain.PoolsRequest) (domain.PoolsResponse, error)
+	PoolDetail(ctx context.Context, req domain.PoolDetailRequest) (domain.PoolDetail, error)
+	Tokens(ctx context.Context, req domain.TokensRequest) (domain.TokensResponse, error)
+	// WatchBlocks delivers every new block number until ctx is done. from
+	// and to name a pair the backend may use to follow the chain.
+	WatchBlocks(ctx context.Context, from, to string) (<-chan uint64, error)
+}
+
+// swapQuote is a quote with every amount in base units of the output token,
+// so outputs below one whole token survive.
+type swapQuote struct {
+	AmountOut *big.Int
+	Best      poolQuote
+	Quotes    []poolQuote
+}
+
+// poolQuote is what one pool pays out, in base units. TVL is in USD.
+type poolQuote struct {
+	DEX       string
+	Pool      string
+	AmountOut *big.Int
+	TVL       string
+}
+
+type cli struct {
+	backend backend
+	options globalOptions
+	out     io.Writer
+}
+
+func newCLI(ctx context.Context, options globalOptions, out io.Writer) (*cli, error) {
+	var b backend
+	if options.rpcURL != "" {
+		embedded, err := newEmbeddedBackend(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		b = embedded
+	} else {
+		b = newHTTPBackend(options.server, options.apiKey, options.timeout)
+	}
+
+	return &cli{backend: b, options: options, out: out}, nil
+}
+
+func (c *cli) close() {
+	if closer, ok := c.backend.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+// call runs one backend request bounded by the -timeout flag.
+func call[T any](ctx context.Context, c *cli, fn func(context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.options.timeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+// embeddedBackend runs the aggregator in-process against the RPC node, with
+// the subgraph when one is given.
+type embeddedBackend struct {
+	domain.UsecaseInterface
+	aggregator *aggregator.Aggregator
+	subgraph   bool
+}
+
+func newEmbeddedBackend(ctx context.Context, options globalOptions) (*embeddedBackend, error) {
+	aggregatorOptions := []aggregator.Option{
+		aggregator.WithRPCURL(options.rpcURL),
+		aggregator.WithRPCTimeout(options.timeout),
+		aggregator.WithMinTVL(options.minTVL),
+	}
+	if options.subgraph != "" {
+		schema, _ := thegraph.SchemaByName(thegraph.SchemaUniswapV2)
+		aggregatorOptions = append(aggregatorOptions, aggregator.WithSubgraphs([]thegraph.Endpoint{
+			{URL: options.subgraph, DEX: "UniswapV2", Schema: schema},
+		}, thegraph.Options{
+			RequestTimeout:  options.timeout,
+			MaxRetries:      2,
+			RetryBackoff:    200 * time.Millisecond,
+			MaxRetryBackoff: 2 * time.Second,
+			PageSize:        1000,
+		}))
+	}
+
+	agg, err := aggregator.New(aggregatorOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &embeddedBackend{
+		UsecaseInterface: agg.Usecase(),
+		aggregator:       agg,
+		subgraph:         options.subgraph != "",
+	}, nil
+}
+
+// Pools crawls the subgraph on first use; without one there is no pool
+// universe to list.
+func (b *embeddedBackend) Pools(ctx context.Context, req domain.PoolsRequest) (domain.PoolsResponse, error) {
+	if !b.subgraph {
+		return domain.PoolsResponse{}, fmt.Errorf("listing pools in embedded mode needs -subgraph")
+	}
+	if !b.aggregator.PoolsCrawled() {
+		if err := b.aggregator.RefreshPools(ctx); err != nil {
+			return domain.PoolsResponse{}, err
+		}
+	}
+
+	return b.UsecaseInterface.Pools(ctx, req)
+}
+
+func (b *embeddedBackend) SwapQuote(ctx context.Context, from, to domain.Token, amountIn *big.Int) (swapQuote, error) {
+	quote, err := b.aggregator.Quote(ctx, from.Address, to.Address, amountIn)
+	if err != nil {
+		return swapQuote{}, err
+	}
+
+	result := swapQuote{AmountOut: quote.AmountOut, Best: embeddedPoolQuote(quote.Best)}
+	for _, poolQuote := range quote.Quotes {
+		result.Quotes = append(result.Quotes, embeddedPoolQuote(poolQuote))
+	}
+	return result, nil
+}
+
+func embeddedPoolQuote(quote domain.PoolQuote) poolQuote {
+	result := poolQuote{DEX: quote.DEX, Pool: quote.Pool, AmountOut: quote.AmountOut}
+	if quote.PoolInfo != nil {
+		result.TVL = quote.PoolInfo.TVL
+	}
+	return result
+}
+
+func (b *embeddedBackend) WatchBlocks(ctx context.Context, _, _ string) (<-chan uint64, error) {
+	blocks, unsubscribe := b.aggregator.Blocks().Subscribe()
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+	}()
+	go b.aggregator.Run(ctx)
+
+	return blocks, nil
+}
@@ -0,0 +1,421 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/ethereum"
+)
+
+func newFlagSet(name, args string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: dexcli %s %s\n", name, args)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// watch renders once, or with watchMode on every new block until ctx is
+// done. A failed refresh is reported and the next block retried.
+func (c *cli) watch(ctx context.Context, watchMode bool, from, to string, render func(ctx context.Context, blockNumber uint64) error) error {
+	if !watchMode {
+		return render(ctx, 0)
+	}
+
+	blocks, err := c.backend.WatchBlocks(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to follow new blocks: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case blockNumber, ok := <-blocks:
+			if !ok {
+				return errors.New("block stream ended")
+			}
+			if err := render(ctx, blockNumber); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				fmt.Fprintf(os.Stderr, "block %d: %v\n", blockNumber, err)
+			}
+		}
+	}
+}
+
+// blockHeader separates refreshes in watch mode.
+func (c *cli) blockHeader(blockNumber uint64) {
+	if blockNumber > 0 && c.options.output == outputTable {
+		fmt.Fprintf(c.out, "\nblock %d\n", blockNumber)
+	}
+}
+
+func (c *cli) printJSON(v interface{}, watchMode bool) error {
+	return printJSON(c.out, v, watchMode)
+}
+
+// quoteOutput is the JSON form of a quote, with amounts both in token units
+// and in base units.
+type quoteOutput struct {
+	From         string            `json:"from"`
+	To           string            `json:"to"`
+	Amount       string            `json:"amount"`
+	AmountOut    string            `json:"amount_out"`
+	AmountOutRaw string            `json:"amount_out_raw"`
+	BestPool     string            `json:"best_pool"`
+	Quotes       []poolQuoteOutput `json:"quotes"`
+}
+
+type poolQuoteOutput struct {
+	DEX          string `json:"dex"`
+	Pool         string `json:"pool"`
+	AmountOut    string `json:"amount_out"`
+	AmountOutRaw string `json:"amount_out_raw"`
+	TVL          string `json:"tvl,omitempty"`
+}
+
+func runQuote(ctx context.Context, c *cli, args []string) error {
+	var from, to, amount string
+	var watchMode bool
+
+	flags := newFlagSet("quote", "-from SYMBOL -to SYMBOL -amount N [-watch]")
+	flags.StringVar(&from, "from", "", "token to sell")
+	flags.StringVar(&to, "to", "", "token to buy")
+	flags.StringVar(&amount, "amount", "", "whole tokens to sell")
+	flags.BoolVar(&watchMode, "watch", false, "refresh on every new block")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if from == "" || to == "" || amount == "" {
+		flags.Usage()
+		return errors.New("-from, -to and -amount are required")
+	}
+	if whole, ok := new(big.Int).SetString(amount, 10); !ok || whole.Sign() <= 0 {
+		return fmt.Errorf("-amount must be a positive whole number of tokens, got %q", amount)
+	}
+
+	// The token list gives the decimals for converting amounts, so outputs
+	// below one whole token are shown rather than truncated to 0.
+	tokens, err := call(ctx, c, func(ctx context.Context) (domain.TokensResponse, error) {
+		return c.backend.Tokens(ctx, domain.TokensRequest{})
+	})
+	if err != nil {
+		return err
+	}
+	fromToken, err := quoteToken(tokens, from)
+	if err != nil {
+		return err
+	}
+	toToken, err := quoteToken(tokens, to)
+	if err != nil {
+		return err
+	}
+
+	amountIn, err := parseUnits(amount, fromToken.Decimals)
+	if err != nil {
+		return err
+	}
+
+	return c.watch(ctx, watchMode, fromToken.Symbol, toToken.Symbol, func(ctx context.Context, blockNumber uint64) error {
+		quote, err := call(ctx, c, func(ctx context.Context) (swapQuote, error) {
+			return c.backend.SwapQuote(ctx, fromToken, toToken, amountIn)
+		})
+		if err != nil {
+			return err
+		}
+
+		if c.options.output == outputJSON {
+			output := quoteOutput{
+				From:         fromToken.Symbol,
+				To:           toToken.Symbol,
+				Amount:       amount,
+				AmountOut:    formatUnits(quote.AmountOut.String(), toToken.Decimals),
+				AmountOutRaw: quote.AmountOut.String(),
+				BestPool:     quote.Best.Pool,
+			}
+			for _, poolQuote := range quote.Quotes {
+				output.Quotes = append(output.Quotes, poolQuoteOutput{
+					DEX:          poolQuote.DEX,
+					Pool:         poolQuote.Pool,
+					AmountOut:    formatUnits(poolQuote.AmountOut.String(), toToken.Decimals),
+					AmountOutRaw: poolQuote.AmountOut.String(),
+					TVL:          poolQuote.TVL,
+				})
+			}
+			return c.printJSON(output, watchMode)
+		}
+
+		c.blockHeader(blockNumber)
+		fmt.Fprintf(c.out, "%s %s -> %s %s via %s\n\n", amount, fromToken.Symbol, formatUnits(quote.AmountOut.String(), toToken.Decimals), toToken.Symbol, quote.Best.DEX)
+
+		t := newTable(c.out, "", "DEX", "POOL", "OUTPUT", "TVL (USD)")
+		for _, poolQuote := range quote.Quotes {
+			best := ""
+			if poolQuote.Pool == quote.Best.Pool {
+				best = "*"
+			}
+			t.row(best, poolQuote.DEX, poolQuote.Pool, formatUnits(poolQuote.AmountOut.String(), toToken.Decimals), poolQuote.TVL)
+		}
+		return t.flush()
+	})
+}
+
+// quoteToken finds a token in the list by symbol or address. The native
+// asset trades through WETH, so it takes WETH's address and keeps its own
+// symbol.
+func quoteToken(tokens domain.TokensResponse, name string) (domain.Token, error) {
+	for _, token := range tokens.Tokens {
+		if !strings.EqualFold(token.Symbol, name) && !strings.EqualFold(token.Address, name) {
+			continue
+		}
+		if token.Symbol != ethereum.NativeTokenSymbol {
+			return token, nil
+		}
+		for _, wrapped := range tokens.Tokens {
+			if wrapped.Symbol == "WETH" {
+				wrapped.Symbol = token.Symbol
+				return wrapped, nil
+			}
+		}
+		return domain.Token{}, fmt.Errorf("%s trades through WETH, which is not a known token", token.Symbol)
+	}
+	return domain.Token{}, fmt.Errorf("unknown token %q, see dexcli tokens", name)
+}
+
+// estimateOutput is the JSON form of an estimate, with amounts both in
+// token units and in base units.
+type estimateOutput struct {
+	Pool         string          `json:"pool"`
+	PoolType     domain.PoolType `json:"pool_type"`
+	Src          string          `json:"src"`
+	Dst          string          `json:"dst"`
+	SrcAmount    string          `json:"src_amount"`
+	DstAmount    string          `json:"dst_amount"`
+	DstAmountRaw string          `json:"dst_amount_raw"`
+}
+
+func runEstimate(ctx context.Context, c *cli, args []string) error {
+	var pool, src, dst, amount string
+	var raw, watchMode bool
+
+	flags := newFlagSet("estimate", "-pool ADDRESS -src TOKEN -dst TOKEN -amount N [-raw] [-watch]")
+	flags.StringVar(&pool, "pool", "", "pool address")
+	flags.StringVar(&src, "src", "", "token to sell, by address or by the pool token's symbol")
+	flags.StringVar(&dst, "dst", "", "token to buy, by address or by the pool token's symbol")
+	flags.StringVar(&amount, "amount", "", "amount to sell in token units, e.g. 1.5")
+	flags.BoolVar(&raw, "raw", false, "take and print amounts in base units")
+	flags.BoolVar(&watchMode, "watch", false, "refresh on every new block")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if pool == "" || src == "" || dst == "" || amount == "" {
+		flags.Usage()
+		return errors.New("-pool, -src, -dst and -amount are required")
+	}
+
+	// The pool's tokens give the decimals for converting amounts and let
+	// src and dst be given by symbol.
+	detail, err := call(ctx, c, func(ctx context.Context) (domain.PoolDetail, error) {
+		return c.backend.PoolDetail(ctx, domain.PoolDetailRequest{Address: pool})
+	})
+	if err != nil {
+		return err
+	}
+
+	srcToken, err := poolToken(detail, src)
+	if err != nil {
+		return err
+	}
+	dstToken, err := poolToken(detail, dst)
+	if err != nil {
+		return err
+	}
+
+	srcAmount := amount
+	if !raw {
+		units, err := parseUnits(amount, srcToken.Decimals)
+		if err != nil {
+			return err
+		}
+		srcAmount = units.String()
+	}
+
+	req := domain.EstimateRequest{Pool: pool, Src: srcToken.Address, Dst: dstToken.Address, SrcAmount: srcAmount}
+
+	return c.watch(ctx, watchMode, srcToken.Symbol, dstToken.Symbol, func(ctx context.Context, blockNumber uint64) error {
+		response, err := call(ctx, c, func(ctx context.Context) (domain.EstimateResponse, error) {
+			return c.backend.Estimate(ctx, req)
+		})
+		if err != nil {
+			return err
+		}
+
+		dstAmount := response.DstAmount
+		displayAmount := amount
+		if !raw {
+			dstAmount = formatUnits(response.DstAmount, dstToken.Decimals)
+		}
+
+		if c.options.output == outputJSON {
+			return c.printJSON(estimateOutput{
+				Pool:         pool,
+				PoolType:     response.PoolType,
+				Src:          srcToken.Address,
+				Dst:          dstToken.Address,
+				SrcAmount:    displayAmount,
+				DstAmount:    dstAmount,
+				DstAmountRaw: response.DstAmount,
+			}, watchMode)
+		}
+
+		c.blockHeader(blockNumber)
+		fmt.Fprintf(c.out, "%s %s -> %s %s on %s pool %s\n", displayAmount, srcToken.Symbol, dstAmount, dstToken.Symbol, response.PoolType, pool)
+		return nil
+	})
+}
+
+// poolToken matches token against the pool's tokens by address or symbol.
+func poolToken(detail domain.PoolDetail, token string) (domain.Token, error) {
+	for _, candidate := range []domain.Token{detail.Token0, detail.Token1} {
+		if strings.EqualFold(candidate.Address, token) || strings.EqualFold(candidate.Symbol, token) {
+			return candidate, nil
+		}
+	}
+	return domain.Token{}, fmt.Errorf("pool %s trades %s/%s, not %s", detail.Address, detail.Token0.Symbol, detail.Token1.Symbol, token)
+}
+
+func runPrice(ctx context.Context, c *cli, args []string) error {
+	var req domain.PriceRequest
+	var watchMode bool
+
+	flags := newFlagSet("price", "-base SYMBOL -quote SYMBOL [-watch]")
+	flags.StringVar(&req.Base, "base", "", "token to price")
+	flags.StringVar(&req.Quote, "quote", "", "token to price it in")
+	flags.BoolVar(&watchMode, "watch", false, "refresh on every new block")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if req.Base == "" || req.Quote == "" {
+		flags.Usage()
+		return errors.New("-base and -quote are required")
+	}
+
+	return c.watch(ctx, watchMode, req.Base, req.Quote, func(ctx context.Context, blockNumber uint64) error {
+		response, err := call(ctx, c, func(ctx context.Context) (domain.PriceResponse, error) {
+			return c.backend.Price(ctx, req)
+		})
+		if err != nil {
+			return err
+		}
+		if c.options.output == outputJSON {
+			return c.printJSON(response, watchMode)
+		}
+
+		c.blockHeader(blockNumber)
+		fmt.Fprintf(c.out, "1 %s = %s %s at block %d via %s\n\n", response.BaseToken, response.Price, response.QuoteToken, response.BlockNumber, strings.Join(response.Route, " -> "))
+
+		t := newTable(c.out, "DEX", "POOL", "PAIR", "PRICE", "BASE RESERVE", "QUOTE RESERVE")
+		for _, pool := range response.Pools {
+			t.row(pool.DEX, pool.Pool, pool.BaseToken+"/"+pool.QuoteToken, pool.Price, pool.BaseReserve, pool.QuoteReserve)
+		}
+		return t.flush()
+	})
+}
+
+func runPools(ctx context.Context, c *cli, args []string) error {
+	var req domain.PoolsRequest
+
+	flags := newFlagSet("pools", "[-token TOKEN] [-dex DEX] | ADDRESS")
+	flags.StringVar(&req.Token, "token", "", "only pools holding this token symbol or address")
+	flags.StringVar(&req.DEX, "dex", "", "only pools of this DEX")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		return c.poolDetail(ctx, flags.Arg(0))
+	}
+
+	response, err := call(ctx, c, func(ctx context.Context) (domain.PoolsResponse, error) {
+		return c.backend.Pools(ctx, req)
+	})
+	if err != nil {
+		return err
+	}
+	if c.options.output == outputJSON {
+		return c.printJSON(response, false)
+	}
+
+	t := newTable(c.out, "ADDRESS", "DEX", "PAIR", "RESERVE0", "RESERVE1", "TVL (USD)")
+	for _, pool := range response.Pools {
+		t.row(pool.Address, pool.DEX, pool.Token0Symbol+"/"+pool.Token1Symbol, pool.Reserve0, pool.Reserve1, pool.TVL)
+	}
+	if err := t.flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "\n%d pools\n", response.Count)
+	return nil
+}
+
+func (c *cli) poolDetail(ctx context.Context, address string) error {
+	detail, err := call(ctx, c, func(ctx context.Context) (domain.PoolDetail, error) {
+		return c.backend.PoolDetail(ctx, domain.PoolDetailRequest{Address: address})
+	})
+	if err != nil {
+		return err
+	}
+	if c.options.output == outputJSON {
+		return c.printJSON(detail, false)
+	}
+
+	info := detail.PoolInfo
+	t := newTable(c.out)
+	t.row("Address", detail.Address)
+	t.row("DEX", detail.DEX)
+	t.row("Type", string(detail.PoolType))
+	t.row("Token0", detail.Token0.Symbol+" "+detail.Token0.Address)
+	t.row("Token1", detail.Token1.Symbol+" "+detail.Token1.Address)
+	t.row("Reserve0", info.Reserve0+" "+detail.Token0.Symbol)
+	t.row("Reserve1", info.Reserve1+" "+detail.Token1.Symbol)
+	t.row("TVL (USD)", info.TVL+" ("+info.TVLSource+")")
+	if info.TVLSource == domain.TVLSourceSubgraph {
+		t.row("Volume 24h", info.Volume24h)
+		t.row("Fees 24h", info.Fees24h)
+	}
+	t.row("Active", strconv.FormatBool(info.IsActive))
+	t.row("Block", strconv.FormatUint(detail.BlockNumber, 10))
+	return t.flush()
+}
+
+func runTokens(ctx context.Context, c *cli, args []string) error {
+	flags := newFlagSet("tokens", "")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	response, err := call(ctx, c, func(ctx context.Context) (domain.TokensResponse, error) {
+		return c.backend.Tokens(ctx, domain.TokensRequest{})
+	})
+	if err != nil {
+		return err
+	}
+	if c.options.output == outputJSON {
+		return c.printJSON(response, false)
+	}
+
+	t := newTable(c.out, "SYMBOL", "NAME", "ADDRESS", "DECIMALS")
+	for _, token := range response.Tokens {
+		t.row(token.Symbol, token.Name, token.Address, strconv.Itoa(int(token.Decimals)))
+	}
+	return t.flush()
+}
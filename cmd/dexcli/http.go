@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/client"
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+// httpBackend calls a running aggregator server's REST API through the
+// client package, which retries throttled and transient failures.
+type httpBackend struct {
+	client *client.Client
+}
+
+func newHTTPBackend(baseURL, apiKey string, timeout time.Duration) *httpBackend {
+	return &httpBackend{
+		client: client.New(baseURL, client.WithAPIKey(apiKey), client.WithTimeout(timeout)),
+	}
+}
+
+// SwapQuote ranks the pools through /quote, which reports whole tokens
+// only, and then reads every quoted pool's output in base units through one
+// batch of estimates. The server quotes whole tokens, so amountIn must be
+// one.
+func (b *httpBackend) SwapQuote(ctx context.Context, from, to domain.Token, amountIn *big.Int) (swapQuote, error) {
+	whole, rest := new(big.Int).QuoRem(amountIn, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(from.Decimals)), nil), new(big.Int))
+	if rest.Sign() != 0 {
+		return swapQuote{}, fmt.Errorf("the server quotes whole tokens only")
+	}
+
+	quote, err := b.client.Quote(ctx, from.Symbol, to.Symbol, whole)
+	if err != nil {
+		return swapQuote{}, err
+	}
+
+	batch := domain.BatchRequest{Estimates: make([]domain.EstimateRequest, len(quote.All))}
+	for i, dexQuote := range quote.All {
+		batch.Estimates[i] = domain.EstimateRequest{Pool: dexQuote.Pool, Src: from.Address, Dst: to.Address, SrcAmount: amountIn.String()}
+	}
+	estimates, err := b.client.BatchQuote(ctx, batch)
+	if err != nil {
+		return swapQuote{}, err
+	}
+	if len(estimates.Estimates) != len(quote.All) {
+		return swapQuote{}, fmt.Errorf("server answered %d of %d estimates", len(estimates.Estimates), len(quote.All))
+	}
+
+	var result swapQuote
+	for i, dexQuote := range quote.All {
+		estimate := estimates.Estimates[i]
+		if estimate.Err != nil {
+			return swapQuote{}, fmt.Errorf("pool %s: %w", dexQuote.Pool, estimate.Err)
+		}
+		if estimate.Estimate == nil {
+			return swapQuote{}, fmt.Errorf("pool %s: empty estimate", dexQuote.Pool)
+		}
+
+		poolQuote := poolQuote{DEX: dexQuote.DEX, Pool: dexQuote.Pool, AmountOut: estimate.Estimate.DstAmount}
+		if dexQuote.PoolInfo != nil {
+			poolQuote.TVL = dexQuote.PoolInfo.TVL
+		}
+		result.Quotes = append(result.Quotes, poolQuote)
+		if dexQuote.Pool == quote.Best.Pool {
+			result.Best = poolQuote
+			result.AmountOut = poolQuote.AmountOut
+		}
+	}
+	if result.AmountOut == nil {
+		return swapQuote{}, fmt.Errorf("best pool %s is not among the quoted pools", quote.Best.Pool)
+	}
+	return result, nil
+}
+
+func (b *httpBackend) Estimate(ctx context.Context, req domain.EstimateRequest) (domain.EstimateResponse, error) {
+	srcAmount, ok := new(big.Int).SetString(req.SrcAmount, 10)
+	if !ok {
+		return domain.EstimateResponse{}, fmt.Errorf("invalid amount %q", req.SrcAmount)
+	}
+
+	estimate, err := b.client.Estimate(ctx, req.Pool, req.Src, req.Dst, srcAmount)
+	if err != nil {
+		return domain.EstimateResponse{}, err
+	}
+	return domain.EstimateResponse{DstAmount: estimate.DstAmount.String(), PoolType: estimate.PoolType}, nil
+}
+
+func (b *httpBackend) Price(ctx context.Context, req domain.PriceRequest) (domain.PriceResponse, error) {
+	response, err := b.client.Price(ctx, req.Base, req.Quote)
+	if err != nil {
+		return domain.PriceResponse{}, err
+	}
+	return *response, nil
+}
+
+func (b *httpBackend) Pools(ctx context.Context, req domain.PoolsRequest) (domain.PoolsResponse, error) {
+	response, err := b.client.Pools(ctx, req)
+	if err != nil {
+		return domain.PoolsResponse{}, err
+	}
+	return *response, nil
+}
+
+func (b *httpBackend) PoolDetail(ctx context.Context, req domain.PoolDetailRequest) (domain.PoolDetail, error) {
+	response, err := b.client.PoolDetail(ctx, req.Address)
+	if err != nil {
+		return domain.PoolDetail{}, err
+	}
+	return *response, nil
+}
+
+func (b *httpBackend) Tokens(ctx context.Context, _ domain.TokensRequest) (domain.TokensResponse, error) {
+	response, err := b.client.Tokens(ctx)
+	if err != nil {
+		return domain.TokensResponse{}, err
+	}
+	return *response, nil
+}
+
+// WatchBlocks follows the server's per-block price stream for the pair and
+// reports the block number of each event, including blocks whose tick
+// failed.
+func (b *httpBackend) WatchBlocks(ctx context.Context, from, to string) (<-chan uint64, error) {
+	events, err := b.client.WatchPrice(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make(chan uint64, 1)
+	go func() {
+		defer close(blocks)
+
+		for event := range events {
+			if event.BlockNumber == 0 {
+				continue
+			}
+			select {
+			case blocks <- event.BlockNumber:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return blocks, nil
+}
@@ -0,0 +1,125 @@
+// Command dexcli quotes swaps from the terminal, either through a running
+// server or by embedding the aggregator against an Ethereum RPC node.
+//
+//	dexcli [global flags] <command> [command flags]
+//
+// Commands: quote, estimate, price, pools, tokens.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const defaultServer = "http://localhost:1337"
+
+type globalOptions struct {
+	server   string
+	apiKey   string
+	rpcURL   string
+	subgraph string
+	minTVL   float64
+	output   string
+	timeout  time.Duration
+}
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, app *cli, args []string) error
+}
+
+var commands = []command{
+	{"quote", "best quote for swapping an amount of one token into another", runQuote},
+	{"estimate", "output of a swap on one pool", runEstimate},
+	{"price", "reserve-weighted spot price of a pair", runPrice},
+	{"pools", "list pools, or show one pool given its address", runPools},
+	{"tokens", "list known tokens", runTokens},
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "dexcli:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	var options globalOptions
+
+	global := flag.NewFlagSet("dexcli", flag.ContinueOnError)
+	global.StringVar(&options.server, "server", envOr("DEXCLI_SERVER", defaultServer), "aggregator server URL (env DEXCLI_SERVER)")
+	global.StringVar(&options.apiKey, "api-key", os.Getenv("DEXCLI_API_KEY"), "API key sent to the server (env DEXCLI_API_KEY)")
+	global.StringVar(&options.rpcURL, "rpc", os.Getenv("DEXCLI_RPC_URL"), "Ethereum RPC URL; when set the aggregator runs embedded instead of calling -server (env DEXCLI_RPC_URL)")
+	global.StringVar(&options.subgraph, "subgraph", os.Getenv("DEXCLI_SUBGRAPH_URL"), "Uniswap V2 subgraph URL for embedded mode (env DEXCLI_SUBGRAPH_URL)")
+	global.Float64Var(&options.minTVL, "min-tvl", 10000, "minimum pool TVL in USD for embedded mode")
+	global.StringVar(&options.output, "o", outputTable, "output format: table or json")
+	global.DurationVar(&options.timeout, "timeout", 30*time.Second, "timeout of each request")
+	global.Usage = func() { usage(global) }
+
+	if err := global.Parse(args); err != nil {
+		return err
+	}
+	if options.output != outputTable && options.output != outputJSON {
+		return fmt.Errorf("unknown output format %q", options.output)
+	}
+
+	rest := global.Args()
+	if len(rest) == 0 {
+		usage(global)
+		return errors.New("missing command")
+	}
+
+	var selected *command
+	for i := range commands {
+		if commands[i].name == rest[0] {
+			selected = &commands[i]
+		}
+	}
+	if selected == nil {
+		usage(global)
+		return fmt.Errorf("unknown command %q", rest[0])
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app, err := newCLI(ctx, options, stdout)
+	if err != nil {
+		return err
+	}
+	defer app.close()
+
+	err = selected.run(ctx, app, rest[1:])
+	if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+		// Interrupted watch mode.
+		return nil
+	}
+	return err
+}
+
+func usage(global *flag.FlagSet) {
+	out := global.Output()
+	fmt.Fprintln(out, "Usage: dexcli [global flags] <command> [command flags]")
+	fmt.Fprintln(out, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(out, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(out, "\nRun 'dexcli <command> -h' for the command's flags.")
+	fmt.Fprintln(out, "\nGlobal flags:")
+	global.PrintDefaults()
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
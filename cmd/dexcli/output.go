@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printJSON writes v indented, or on one line in watch mode so every
+// refresh is one JSON document per line.
+func printJSON(out io.Writer, v interface{}, compact bool) error {
+	encoder := json.NewEncoder(out)
+	if !compact {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(v)
+}
+
+// table writes tab-separated rows as aligned columns.
+type table struct {
+	w *tabwriter.Writer
+}
+
+func newTable(out io.Writer, header ...string) *table {
+	t := &table{w: tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)}
+	if len(header) > 0 {
+		t.row(header...)
+	}
+	return t
+}
+
+func (t *table) row(columns ...string) {
+	fmt.Fprintln(t.w, strings.Join(columns, "\t"))
+}
+
+func (t *table) flush() error {
+	return t.w.Flush()
+}
+
+// parseUnits converts a decimal amount such as "1.5" into base units.
+func parseUnits(amount string, decimals uint8) (*big.Int, error) {
+	whole, frac, _ := strings.Cut(strings.TrimSpace(amount), ".")
+	if len(frac) > int(decimals) {
+		return nil, fmt.Errorf("amount %s has more than %d decimals", amount, decimals)
+	}
+
+	raw, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", int(decimals)-len(frac)), 10)
+	if !ok || raw.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+	return raw, nil
+}
+
+// formatUnits renders base units as a decimal amount without trailing zeros.
+func formatUnits(raw string, decimals uint8) string {
+	amount, ok := new(big.Int).SetString(raw, 10)
+	if !ok || decimals == 0 {
+		return raw
+	}
+
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, frac := new(big.Int).QuoRem(amount, divisor, new(big.Int))
+
+	fracStr := strings.TrimRight(fmt.Sprintf("%0*s", int(decimals), frac.String()), "0")
+	if fracStr == "" {
+		return whole.String()
+	}
+	return whole.String() + "." + fracStr
+}
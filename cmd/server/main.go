@@ -1,11 +1,23 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+
 	"github.com/DiDinar5/mini-dex-aggregator/config"
 	"github.com/DiDinar5/mini-dex-aggregator/internal/app"
 )
 
 func main() {
-	cfg := config.Load()
-	app.Run(*cfg)
+	configPath := flag.String("config", "", "path to the YAML config file (default $"+config.PathEnv+" or "+config.DefaultPath+")")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	app.Run(*cfg, *configPath)
 }
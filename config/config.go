@@ -1,8 +1,13 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,48 +16,197 @@ type Config struct {
 	Server   ServerConfig   `yaml:"server"`
 	Ethereum EthereumConfig `yaml:"ethereum"`
 	TheGraph TheGraphConfig `yaml:"thegraph"`
+	Stream   StreamConfig   `yaml:"stream"`
+	GRPC     GRPCConfig     `yaml:"grpc"`
+	Auth     AuthConfig     `yaml:"auth"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+	Log      LogConfig      `yaml:"log"`
+	Health   HealthConfig   `yaml:"health"`
+	// Tokens extends the built-in token registry, symbol to address.
+	Tokens map[string]string `yaml:"tokens"`
 }
 
 type ServerConfig struct {
 	Port string `yaml:"port"`
 	Host string `yaml:"host"`
+	// TrustedProxies lists the CIDRs of reverse proxies whose
+	// X-Forwarded-For header names the client. Without any, the client is
+	// the connection's peer address.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// GRPCConfig configures the gRPC listener, which runs next to the REST
+// server on its own port. An empty Port disables it.
+type GRPCConfig struct {
+	Port string `yaml:"port"`
+	Host string `yaml:"host"`
+}
+
+// TracingConfig selects where OpenTelemetry spans go: "none", "stdout" or
+// "otlp" (OTLP over HTTP to OTLPEndpoint).
+type TracingConfig struct {
+	Exporter     string  `yaml:"exporter"`
+	ServiceName  string  `yaml:"service_name"`
+	OTLPEndpoint string  `yaml:"otlp_endpoint"`
+	OTLPInsecure bool    `yaml:"otlp_insecure"`
+	SampleRatio  float64 `yaml:"sample_ratio"`
+}
+
+// LogConfig sets the minimum level (debug, info, warn or error) and the
+// output format ("text" or "json") of the process logs.
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+// HealthConfig tunes /readyz. On shutdown readiness fails for DrainDelay
+// before the listeners close, giving load balancers time to stop routing.
+type HealthConfig struct {
+	MaxHeadAge     time.Duration `yaml:"max_head_age"`
+	MaxSubgraphLag uint64        `yaml:"max_subgraph_lag"`
+	ProbeTimeout   time.Duration `yaml:"probe_timeout"`
+	DrainDelay     time.Duration `yaml:"drain_delay"`
+}
+
+// AuthConfig lists the API keys accepted by the server, inline and/or in a
+// separate YAML file with a top-level "keys" list. With no keys at all the
+// API is open.
+type AuthConfig struct {
+	Keys     []APIKeyConfig `yaml:"keys"`
+	KeysFile string         `yaml:"keys_file"`
+}
+
+// APIKeyConfig is one client key. RateLimit is in requests per second with
+// bursts of up to Burst requests; zero RateLimit or DailyQuota means no limit.
+type APIKeyConfig struct {
+	Name       string  `yaml:"name"`
+	Key        string  `yaml:"key"`
+	RateLimit  float64 `yaml:"rate_limit"`
+	Burst      int     `yaml:"burst"`
+	DailyQuota int     `yaml:"daily_quota"`
 }
 
 type EthereumConfig struct {
-	RPCURL  string `yaml:"rpc_url"`
-	Timeout string `yaml:"timeout"`
+	RPCURL string `yaml:"rpc_url"`
+	// Timeout bounds every RPC call.
+	Timeout           time.Duration   `yaml:"timeout"`
+	BlockPollInterval time.Duration   `yaml:"block_poll_interval"`
+	GasOracle         GasOracleConfig `yaml:"gas_oracle"`
+}
+
+// GasOracleConfig tunes GET /gas and the fees quotes price gas at.
+// BlockCount is the fee history window; each tier's percentile selects the
+// priority fee it pays among recent transactions.
+type GasOracleConfig struct {
+	BlockCount         int     `yaml:"block_count"`
+	SlowPercentile     float64 `yaml:"slow_percentile"`
+	StandardPercentile float64 `yaml:"standard_percentile"`
+	FastPercentile     float64 `yaml:"fast_percentile"`
+}
+
+type StreamConfig struct {
+	MaxSubscriptions  int           `yaml:"max_subscriptions"`
+	SendBuffer        int           `yaml:"send_buffer"`
+	MaxDroppedUpdates int           `yaml:"max_dropped_updates"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	// MaxSSEPerClient caps concurrent /stream/price connections per API
+	// key, or per client IP when authentication is disabled.
+	MaxSSEPerClient int `yaml:"max_sse_per_client"`
 }
 
 type TheGraphConfig struct {
-	UniswapV2URL string  `yaml:"uniswap_v2_url"`
-	MinTVL       float64 `yaml:"min_tvl"`
+	UniswapV2URL     string             `yaml:"uniswap_v2_url"`
+	Endpoints        []SubgraphEndpoint `yaml:"endpoints"`
+	MinTVL           float64            `yaml:"min_tvl"`
+	RequestTimeout   time.Duration      `yaml:"request_timeout"`
+	MaxRetries       int                `yaml:"max_retries"`
+	RetryBackoff     time.Duration      `yaml:"retry_backoff"`
+	MaxRetryBackoff  time.Duration      `yaml:"max_retry_backoff"`
+	BreakerThreshold int                `yaml:"breaker_threshold"`
+	BreakerCooldown  time.Duration      `yaml:"breaker_cooldown"`
+	CacheTTL         time.Duration      `yaml:"cache_ttl"`
+	CacheStaleTTL    time.Duration      `yaml:"cache_stale_ttl"`
+	CacheMaxEntries  int                `yaml:"cache_max_entries"`
+	CrawlInterval    time.Duration      `yaml:"crawl_interval"`
+	CrawlPageSize    int                `yaml:"crawl_page_size"`
 }
 
-func Load() *Config {
-	config, err := loadFromYAML("config.yaml")
-	if err != nil {
-		return defaultConfig()
+// SubgraphEndpoint is an additional subgraph to query. Schema selects the
+// entity model it serves: "uniswap-v2" (default) or "messari". DEX labels
+// the pools it returns.
+type SubgraphEndpoint struct {
+	URL    string `yaml:"url"`
+	DEX    string `yaml:"dex"`
+	Schema string `yaml:"schema"`
+}
+
+// DefaultPath is read when neither the -config flag nor PathEnv names a
+// file. Unlike an explicit path it may be missing, leaving the defaults and
+// environment overrides.
+const (
+	DefaultPath = "config.yaml"
+	PathEnv     = "DEX_CONFIG"
+)
+
+// Load reads the YAML file at path over the defaults, applies environment
+// overrides and validates the result. An empty path falls back to PathEnv
+// and then DefaultPath. Unknown keys in the file are rejected.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		path = os.Getenv(PathEnv)
+	}
+	required := path != ""
+	if !required {
+		path = DefaultPath
+	}
+
+	// Start from the defaults so settings missing from the file keep sane values.
+	config := defaultConfig()
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	case errors.Is(err, fs.ErrNotExist) && !required:
+	default:
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := applyEnv(config, os.LookupEnv); err != nil {
+		return nil, err
 	}
 
-	return config
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
 }
 
-func loadFromYAML(filename string) (*Config, error) {
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		return nil, fmt.Errorf("config file %s not found", filename)
+// LoadKeys returns the inline keys followed by those in KeysFile.
+func (c AuthConfig) LoadKeys() ([]APIKeyConfig, error) {
+	keys := append([]APIKeyConfig(nil), c.Keys...)
+	if c.KeysFile == "" {
+		return keys, nil
 	}
 
-	data, err := os.ReadFile(filename)
+	data, err := os.ReadFile(c.KeysFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, fmt.Errorf("failed to read keys file: %w", err)
 	}
 
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+	var file struct {
+		Keys []APIKeyConfig `yaml:"keys"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse keys file: %w", err)
 	}
 
-	return &config, nil
+	return append(keys, file.Keys...), nil
 }
 
 func defaultConfig() *Config {
@@ -62,12 +216,56 @@ func defaultConfig() *Config {
 			Port: "1337",
 		},
 		Ethereum: EthereumConfig{
-			RPCURL:  "",
-			Timeout: "30s",
+			RPCURL:            "",
+			Timeout:           30 * time.Second,
+			BlockPollInterval: 2 * time.Second,
+			GasOracle: GasOracleConfig{
+				BlockCount:         20,
+				SlowPercentile:     10,
+				StandardPercentile: 50,
+				FastPercentile:     90,
+			},
 		},
 		TheGraph: TheGraphConfig{
-			UniswapV2URL: "",
-			MinTVL:       10000.0, // $10,000 minimum TVL
+			UniswapV2URL:     "",
+			MinTVL:           10000.0, // $10,000 minimum TVL
+			RequestTimeout:   5 * time.Second,
+			MaxRetries:       2,
+			RetryBackoff:     200 * time.Millisecond,
+			MaxRetryBackoff:  2 * time.Second,
+			BreakerThreshold: 5,
+			BreakerCooldown:  30 * time.Second,
+			CacheTTL:         time.Minute,
+			CacheStaleTTL:    5 * time.Minute,
+			CacheMaxEntries:  10000,
+			CrawlInterval:    10 * time.Minute,
+			CrawlPageSize:    1000,
+		},
+		Stream: StreamConfig{
+			MaxSubscriptions:  50,
+			SendBuffer:        32,
+			MaxDroppedUpdates: 10,
+			WriteTimeout:      10 * time.Second,
+			MaxSSEPerClient:   5,
+		},
+		GRPC: GRPCConfig{
+			Host: "localhost",
+			Port: "1338",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "mini-dex-aggregator",
+			SampleRatio: 1.0,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Health: HealthConfig{
+			MaxHeadAge:     2 * time.Minute,
+			MaxSubgraphLag: 50,
+			ProbeTimeout:   3 * time.Second,
+			DrainDelay:     5 * time.Second,
 		},
 	}
 }
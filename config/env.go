@@ -0,0 +1,105 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts every override variable.
+const EnvPrefix = "DEX"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv overrides each setting from the variable named after its YAML
+// path, e.g. DEX_ETHEREUM_RPC_URL for ethereum.rpc_url. Durations use Go
+// syntax ("5s"); lists and maps take a YAML or JSON document.
+func applyEnv(config *Config, lookup func(string) (string, bool)) error {
+	return applyEnvStruct(reflect.ValueOf(config).Elem(), EnvPrefix, lookup)
+}
+
+func applyEnvStruct(v reflect.Value, prefix string, lookup func(string) (string, bool)) error {
+	var errs []error
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + "_" + strings.ToUpper(name)
+
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := applyEnvStruct(field, key, lookup); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		raw, ok := lookup(key)
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(field, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func setFromEnv(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice, reflect.Map:
+		parsed := reflect.New(v.Type())
+		if err := yaml.Unmarshal([]byte(raw), parsed.Interface()); err != nil {
+			return err
+		}
+		v.Set(parsed.Elem())
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/logging"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/thegraph"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/tracing"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ValidationError lists every invalid setting, so one startup attempt
+// reports them all.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) check(ok bool, setting, format string, args ...interface{}) {
+	if !ok {
+		v.problems = append(v.problems, setting+": "+fmt.Sprintf(format, args...))
+	}
+}
+
+func (v *validator) port(setting, port string) {
+	n, err := strconv.Atoi(port)
+	v.check(err == nil && n > 0 && n <= 65535, setting, "must be a port number, got %q", port)
+}
+
+func (v *validator) url(setting, raw string, schemes ...string) {
+	parsed, err := url.Parse(raw)
+	valid := err == nil && parsed.Host != ""
+	if valid {
+		valid = false
+		for _, scheme := range schemes {
+			if parsed.Scheme == scheme {
+				valid = true
+			}
+		}
+	}
+	v.check(valid, setting, "must be a %s URL, got %q", strings.Join(schemes, "/"), raw)
+}
+
+// Validate checks every setting and reports all problems at once.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	v.port("server.port", c.Server.Port)
+	for i, cidr := range c.Server.TrustedProxies {
+		_, _, err := net.ParseCIDR(cidr)
+		v.check(err == nil, fmt.Sprintf("server.trusted_proxies[%d]", i), "must be a CIDR, got %q", cidr)
+	}
+	if c.GRPC.Port != "" {
+		v.port("grpc.port", c.GRPC.Port)
+		v.check(c.GRPC.Port != c.Server.Port || c.GRPC.Host != c.Server.Host, "grpc.port", "must differ from server.port")
+	}
+
+	if c.Ethereum.RPCURL == "" {
+		v.check(false, "ethereum.rpc_url", "is required")
+	} else {
+		v.url("ethereum.rpc_url", c.Ethereum.RPCURL, "http", "https", "ws", "wss")
+	}
+	v.check(c.Ethereum.Timeout > 0, "ethereum.timeout", "must be positive")
+	v.check(c.Ethereum.BlockPollInterval > 0, "ethereum.block_poll_interval", "must be positive")
+	gas := c.Ethereum.GasOracle
+	// Nodes serve at most 1024 blocks of fee history.
+	v.check(gas.BlockCount > 0 && gas.BlockCount <= 1024, "ethereum.gas_oracle.block_count", "must be between 1 and 1024")
+	v.check(gas.SlowPercentile >= 0 && gas.SlowPercentile <= 100, "ethereum.gas_oracle.slow_percentile", "must be between 0 and 100")
+	v.check(gas.StandardPercentile >= gas.SlowPercentile && gas.StandardPercentile <= 100, "ethereum.gas_oracle.standard_percentile", "must be between ethereum.gas_oracle.slow_percentile and 100")
+	v.check(gas.FastPercentile >= gas.StandardPercentile && gas.FastPercentile <= 100, "ethereum.gas_oracle.fast_percentile", "must be between ethereum.gas_oracle.standard_percentile and 100")
+
+	g := c.TheGraph
+	if g.UniswapV2URL != "" {
+		v.url("thegraph.uniswap_v2_url", g.UniswapV2URL, "http", "https")
+	}
+	for i, endpoint := range g.Endpoints {
+		setting := fmt.Sprintf("thegraph.endpoints[%d]", i)
+		v.url(setting+".url", endpoint.URL, "http", "https")
+		_, err := thegraph.SchemaByName(endpoint.Schema)
+		v.check(err == nil, setting+".schema", "must be %q or %q, got %q", thegraph.SchemaUniswapV2, thegraph.SchemaMessari, endpoint.Schema)
+	}
+	v.check(g.MinTVL >= 0, "thegraph.min_tvl", "must not be negative")
+	v.check(g.RequestTimeout > 0, "thegraph.request_timeout", "must be positive")
+	v.check(g.MaxRetries >= 0, "thegraph.max_retries", "must not be negative")
+	v.check(g.RetryBackoff >= 0, "thegraph.retry_backoff", "must not be negative")
+	v.check(g.MaxRetryBackoff >= g.RetryBackoff, "thegraph.max_retry_backoff", "must be at least thegraph.retry_backoff")
+	v.check(g.BreakerThreshold >= 0, "thegraph.breaker_threshold", "must not be negative")
+	v.check(g.BreakerCooldown >= 0, "thegraph.breaker_cooldown", "must not be negative")
+	v.check(g.CacheTTL >= 0, "thegraph.cache_ttl", "must not be negative")
+	v.check(g.CacheStaleTTL >= 0, "thegraph.cache_stale_ttl", "must not be negative")
+	v.check(g.CacheMaxEntries >= 0, "thegraph.cache_max_entries", "must not be negative")
+	v.check(g.CrawlInterval >= 0, "thegraph.crawl_interval", "must not be negative")
+	// The Graph caps page sizes at 1000.
+	v.check(g.CrawlPageSize > 0 && g.CrawlPageSize <= 1000, "thegraph.crawl_page_size", "must be between 1 and 1000")
+
+	v.check(c.Stream.MaxSubscriptions > 0, "stream.max_subscriptions", "must be positive")
+	v.check(c.Stream.SendBuffer > 0, "stream.send_buffer", "must be positive")
+	v.check(c.Stream.MaxDroppedUpdates > 0, "stream.max_dropped_updates", "must be positive")
+	v.check(c.Stream.WriteTimeout > 0, "stream.write_timeout", "must be positive")
+	v.check(c.Stream.MaxSSEPerClient >= 0, "stream.max_sse_per_client", "must not be negative")
+
+	for i, key := range c.Auth.Keys {
+		setting := fmt.Sprintf("auth.keys[%d]", i)
+		v.check(key.Name != "", setting+".name", "is required")
+		v.check(key.Key != "", setting+".key", "is required")
+		v.check(key.RateLimit >= 0, setting+".rate_limit", "must not be negative")
+		v.check(key.Burst >= 0, setting+".burst", "must not be negative")
+		v.check(key.DailyQuota >= 0, setting+".daily_quota", "must not be negative")
+	}
+
+	switch c.Tracing.Exporter {
+	case "", tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	default:
+		v.check(false, "tracing.exporter", "must be %q, %q or %q, got %q", tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP, c.Tracing.Exporter)
+	}
+	v.check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1")
+
+	var level slog.Level
+	v.check(level.UnmarshalText([]byte(c.Log.Level)) == nil, "log.level", "must be debug, info, warn or error, got %q", c.Log.Level)
+	v.check(c.Log.Format == logging.FormatText || c.Log.Format == logging.FormatJSON, "log.format", "must be %q or %q, got %q", logging.FormatText, logging.FormatJSON, c.Log.Format)
+
+	v.check(c.Health.MaxHeadAge >= 0, "health.max_head_age", "must not be negative")
+	v.check(c.Health.ProbeTimeout >= 0, "health.probe_timeout", "must not be negative")
+	v.check(c.Health.DrainDelay >= 0, "health.drain_delay", "must not be negative")
+
+	symbols := make([]string, 0, len(c.Tokens))
+	for symbol := range c.Tokens {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		address := c.Tokens[symbol]
+		v.check(symbol != "" && symbol == strings.ToUpper(symbol), "tokens."+symbol, "symbol must be upper case")
+		v.check(common.IsHexAddress(address), "tokens."+symbol, "must be a token address, got %q", address)
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// APIKeyUsage reports what one API key has consumed. Zero limits mean
+// unlimited.
+type APIKeyUsage struct {
+	Name          string    `json:"name"`
+	RequestsToday int       `json:"requests_today"`
+	DailyQuota    int       `json:"daily_quota"`
+	RateLimit     float64   `json:"rate_limit"`
+	Burst         int       `json:"burst"`
+	RequestsTotal uint64    `json:"requests_total"`
+	RateLimited   uint64    `json:"rate_limited"`
+	QuotaExceeded uint64    `json:"quota_exceeded"`
+	QuotaResetAt  time.Time `json:"quota_reset_at"`
+}
+
+type APIKeyUsageProvider interface {
+	Usage(name string) (APIKeyUsage, bool)
+}
@@ -0,0 +1,24 @@
+package domain
+
+type BatchRequest struct {
+	Quotes    []QuoteRequest    `json:"quotes" validate:"max=100"`
+	Estimates []EstimateRequest `json:"estimates" validate:"max=100"`
+}
+
+// BatchResponse holds one result per requested item, in request order. Every
+// item was evaluated against the state at BlockNumber.
+type BatchResponse struct {
+	BlockNumber uint64                `json:"block_number"`
+	Quotes      []BatchQuoteResult    `json:"quotes"`
+	Estimates   []BatchEstimateResult `json:"estimates"`
+}
+
+type BatchQuoteResult struct {
+	Result *QuoteResponse `json:"result,omitempty"`
+	Error  *ErrorResponse `json:"error,omitempty"`
+}
+
+type BatchEstimateResult struct {
+	Result *EstimateResponse `json:"result,omitempty"`
+	Error  *ErrorResponse    `json:"error,omitempty"`
+}
@@ -0,0 +1,13 @@
+package domain
+
+type CacheStats struct {
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	StaleHits uint64  `json:"stale_hits"`
+	Entries   int     `json:"entries"`
+	HitRatio  float64 `json:"hit_ratio"`
+}
+
+type CacheStatsProvider interface {
+	Stats() CacheStats
+}
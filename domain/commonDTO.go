@@ -1,6 +0,0 @@
-package domain
-
-type CommonResponse struct {
-	Message string `json:"message"`
-	Status  bool   `json:"status"`
-}
@@ -1,7 +1,180 @@
 package domain
 
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrorCode is the machine-readable classification of a failed request.
+type ErrorCode string
+
+const (
+	CodeInvalidInput        ErrorCode = "INVALID_INPUT"
+	CodeUnknownToken        ErrorCode = "UNKNOWN_TOKEN"
+	CodePoolNotFound        ErrorCode = "POOL_NOT_FOUND"
+	CodeNotFound            ErrorCode = "NOT_FOUND"
+	CodeNoLiquidity         ErrorCode = "NO_LIQUIDITY"
+	CodeUnsupportedPool     ErrorCode = "UNSUPPORTED_POOL"
+	CodeUpstreamUnavailable ErrorCode = "UPSTREAM_UNAVAILABLE"
+	CodeTimeout             ErrorCode = "UPSTREAM_TIMEOUT"
+	CodeUnauthorized        ErrorCode = "UNAUTHORIZED"
+	CodeRateLimited         ErrorCode = "RATE_LIMITED"
+	CodeInternal            ErrorCode = "INTERNAL"
+)
+
+// Sentinels for errors.Is checks against a classification.
+var (
+	ErrInvalidInput        = &Error{Code: CodeInvalidInput}
+	ErrUnknownToken        = &Error{Code: CodeUnknownToken}
+	ErrPoolNotFound        = &Error{Code: CodePoolNotFound}
+	ErrNotFound            = &Error{Code: CodeNotFound}
+	ErrNoLiquidity         = &Error{Code: CodeNoLiquidity}
+	ErrUnsupportedPool     = &Error{Code: CodeUnsupportedPool}
+	ErrUpstreamUnavailable = &Error{Code: CodeUpstreamUnavailable}
+	ErrTimeout             = &Error{Code: CodeTimeout}
+	ErrUnauthorized        = &Error{Code: CodeUnauthorized}
+	ErrRateLimited         = &Error{Code: CodeRateLimited}
+)
+
+// Error is a classified failure. Message is safe to show to API clients;
+// Err keeps the underlying cause for logs and errors.Is/As.
+type Error struct {
+	Code    ErrorCode
+	Message string
+	Err     error
+}
+
+func NewError(code ErrorCode, message string, err error) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// NewUpstreamError classifies a failed dependency call, telling deadline
+// overruns apart from the dependency being unreachable or erroring.
+func NewUpstreamError(message string, err error) error {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return err
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return NewError(CodeTimeout, message, err)
+	}
+
+	return NewError(CodeUpstreamUnavailable, message, err)
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Message == "" && e.Err == nil:
+		return string(e.Code)
+	case e.Err == nil:
+		return e.Message
+	case e.Message == "":
+		return e.Err.Error()
+	default:
+		return e.Message + ": " + e.Err.Error()
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches any error of the same code, so errors.Is(err, ErrNoLiquidity)
+// holds for every no-liquidity failure regardless of its message.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code && t.Message == "" && t.Err == nil
+}
+
+// ErrorCodeOf returns the classification of err, treating unclassified
+// errors as internal.
+func ErrorCodeOf(err error) ErrorCode {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CodeTimeout
+	}
+
+	return CodeInternal
+}
+
+// HTTPStatus maps an error code onto the HTTP status returned for it.
+func (c ErrorCode) HTTPStatus() int {
+	switch c {
+	case CodeInvalidInput:
+		return http.StatusBadRequest
+	case CodeUnknownToken, CodePoolNotFound, CodeNotFound:
+		return http.StatusNotFound
+	case CodeNoLiquidity, CodeUnsupportedPool:
+		return http.StatusUnprocessableEntity
+	case CodeUpstreamUnavailable:
+		return http.StatusBadGateway
+	case CodeTimeout:
+		return http.StatusGatewayTimeout
+	case CodeUnauthorized:
+		return http.StatusUnauthorized
+	case CodeRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (c ErrorCode) title() string {
+	switch c {
+	case CodeInvalidInput:
+		return "Invalid input"
+	case CodeUnknownToken:
+		return "Unknown token"
+	case CodePoolNotFound:
+		return "Pool not found"
+	case CodeNotFound:
+		return "Not found"
+	case CodeNoLiquidity:
+		return "No liquidity"
+	case CodeUnsupportedPool:
+		return "Unsupported pool"
+	case CodeUpstreamUnavailable:
+		return "Upstream unavailable"
+	case CodeTimeout:
+		return "Upstream timeout"
+	case CodeUnauthorized:
+		return "Unauthorized"
+	case CodeRateLimited:
+		return "Too many requests"
+	default:
+		return "Internal error"
+	}
+}
+
 type ErrorResponse struct {
-	Error       string `json:"error"`
-	Code        int    `json:"code"`
-	Description string `json:"description"`
+	Error       string    `json:"error"`
+	Code        int       `json:"code"`
+	ErrorCode   ErrorCode `json:"error_code"`
+	Description string    `json:"description"`
+}
+
+// NewErrorResponse reports err to API clients. Only the client-safe
+// Message is described; the wrapped cause can carry upstream URLs and keys
+// and is left to the logs.
+func NewErrorResponse(err error) ErrorResponse {
+	code := ErrorCodeOf(err)
+
+	description := code.title()
+	var domainErr *Error
+	if errors.As(err, &domainErr) && domainErr.Message != "" {
+		description = domainErr.Message
+	}
+
+	return ErrorResponse{
+		Error:       code.title(),
+		Code:        code.HTTPStatus(),
+		ErrorCode:   code,
+		Description: description,
+	}
 }
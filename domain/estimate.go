@@ -8,5 +8,6 @@ type EstimateRequest struct {
 }
 
 type EstimateResponse struct {
-	DstAmount string `json:"dst_amount"`
+	DstAmount string   `json:"dst_amount"`
+	PoolType  PoolType `json:"pool_type"`
 }
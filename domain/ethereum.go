@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"math/big"
 )
 
@@ -12,8 +13,31 @@ type PoolReserves struct {
 	BlockNumber uint64   `json:"block_number"`
 }
 
+// PoolType identifies the AMM interface a pool contract implements.
+type PoolType string
+
+const (
+	PoolTypeUniswapV2 PoolType = "uniswap_v2"
+	PoolTypeUniswapV3 PoolType = "uniswap_v3"
+	PoolTypeUnknown   PoolType = "unknown"
+)
+
 type TokenInfo struct {
 	Address  string `json:"address"`
 	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
 	Decimals uint8  `json:"decimals"`
 }
+
+type blockNumberKey struct{}
+
+// WithBlockNumber pins every chain read made with the returned context to
+// one block, so related reads observe a consistent snapshot.
+func WithBlockNumber(ctx context.Context, blockNumber uint64) context.Context {
+	return context.WithValue(ctx, blockNumberKey{}, blockNumber)
+}
+
+func BlockNumberFromContext(ctx context.Context) (uint64, bool) {
+	blockNumber, ok := ctx.Value(blockNumberKey{}).(uint64)
+	return blockNumber, ok
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"math/big"
+)
+
+// GasFees is the EIP-1559 fee market at BlockNumber, in wei per gas.
+type GasFees struct {
+	BaseFee     *big.Int
+	PriorityFee *big.Int
+	BlockNumber uint64
+}
+
+// GasPrice is what a transaction included at these fees pays per gas.
+func (f *GasFees) GasPrice() *big.Int {
+	return new(big.Int).Add(f.BaseFee, f.PriorityFee)
+}
+
+// GasPriceSource suggests the fees quotes price their gas costs at.
+type GasPriceSource interface {
+	GasFees(ctx context.Context) (*GasFees, error)
+}
+
+// GasInfo reports the fees a quote's gas costs were priced at, in wei per
+// gas.
+type GasInfo struct {
+	BaseFee     string `json:"base_fee"`
+	PriorityFee string `json:"priority_fee"`
+	GasPrice    string `json:"gas_price"`
+	BlockNumber uint64 `json:"block_number"`
+}
+
+// GasTier is one speed of a fee suggestion, in wei per gas. Percentile is
+// the share of recent transactions its priority fee outbids.
+type GasTier struct {
+	Percentile           float64
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+}
+
+// GasSuggestion holds EIP-1559 fees for a transaction sent after
+// BlockNumber, derived from the fee history of the last BlockCount blocks.
+// BaseFee is the base fee of the next block.
+type GasSuggestion struct {
+	BlockNumber uint64
+	BlockCount  int
+	BaseFee     *big.Int
+	Slow        GasTier
+	Standard    GasTier
+	Fast        GasTier
+}
+
+// GasSuggestionProvider suggests transaction fees at the chain head.
+type GasSuggestionProvider interface {
+	GasSuggestion(ctx context.Context) (*GasSuggestion, error)
+}
+
+// GasTierInfo is a GasTier with its fees in wei per gas.
+type GasTierInfo struct {
+	Percentile           float64 `json:"percentile"`
+	MaxFeePerGas         string  `json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas string  `json:"max_priority_fee_per_gas"`
+}
+
+// GasResponse is the body of GET /gas.
+type GasResponse struct {
+	BlockNumber uint64      `json:"block_number"`
+	BlockCount  int         `json:"block_count"`
+	BaseFee     string      `json:"base_fee"`
+	Slow        GasTierInfo `json:"slow"`
+	Standard    GasTierInfo `json:"standard"`
+	Fast        GasTierInfo `json:"fast"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// HealthStatus is the outcome of a health check.
+type HealthStatus string
+
+const (
+	HealthOK HealthStatus = "ok"
+	// HealthDegraded marks an optional dependency that is failing; the
+	// service still answers, with reduced data.
+	HealthDegraded HealthStatus = "degraded"
+	HealthFailing  HealthStatus = "failing"
+	// HealthDraining is reported once shutdown has begun.
+	HealthDraining HealthStatus = "draining"
+)
+
+type HealthResponse struct {
+	Status HealthStatus `json:"status"`
+}
+
+// DependencyCheck is the result of probing one dependency.
+type DependencyCheck struct {
+	Name      string       `json:"name"`
+	Status    HealthStatus `json:"status"`
+	Message   string       `json:"message,omitempty"`
+	LatencyMs int64        `json:"latency_ms,omitempty"`
+	// BlockNumber is the chain head for the RPC check and the indexed block
+	// for subgraph checks.
+	BlockNumber    uint64  `json:"block_number,omitempty"`
+	HeadAgeSeconds float64 `json:"head_age_seconds,omitempty"`
+	// LagBlocks is how far a subgraph trails the chain head.
+	LagBlocks *uint64 `json:"lag_blocks,omitempty"`
+}
+
+type ReadinessResponse struct {
+	Status HealthStatus      `json:"status"`
+	Checks []DependencyCheck `json:"checks"`
+}
+
+// Ready reports whether traffic should be routed to the instance.
+func (r ReadinessResponse) Ready() bool {
+	return r.Status == HealthOK || r.Status == HealthDegraded
+}
+
+type ReadinessProvider interface {
+	Readiness(ctx context.Context) ReadinessResponse
+}
+
+type BlockHeader struct {
+	Number uint64
+	Time   time.Time
+}
+
+// ChainHeadSource reads the latest block header from the node.
+type ChainHeadSource interface {
+	HeadBlock(ctx context.Context) (BlockHeader, error)
+}
+
+// SubgraphHead is the block a subgraph endpoint has indexed up to, or the
+// error that prevented reading it.
+type SubgraphHead struct {
+	DEX         string
+	BlockNumber uint64
+	Err         error
+}
+
+type SubgraphHeadSource interface {
+	IndexedBlocks(ctx context.Context) []SubgraphHead
+}
@@ -8,6 +8,12 @@ import (
 type UsecaseInterface interface {
 	Estimate(ctx context.Context, req EstimateRequest) (EstimateResponse, error)
 	Quote(ctx context.Context, req QuoteRequest) (QuoteResponse, error)
+	Pools(ctx context.Context, req PoolsRequest) (PoolsResponse, error)
+	PoolDetail(ctx context.Context, req PoolDetailRequest) (PoolDetail, error)
+	Tokens(ctx context.Context, req TokensRequest) (TokensResponse, error)
+	BatchQuote(ctx context.Context, req BatchRequest) (BatchResponse, error)
+	PriceTick(ctx context.Context, req PriceTickRequest) (PriceTick, error)
+	Price(ctx context.Context, req PriceRequest) (PriceResponse, error)
 }
 
 type EthereumServiceInterface interface {
@@ -16,4 +22,12 @@ type EthereumServiceInterface interface {
 	FindPool(ctx context.Context, dexName, tokenA, tokenB string) (string, error)
 	GetQuoteForPool(ctx context.Context, poolAddress, tokenIn string, amountIn *big.Int) (*big.Int, error)
 	FindAllPools(ctx context.Context, tokenA, tokenB string) (map[string]string, error)
+	DetectPoolType(ctx context.Context, poolAddress string) (PoolType, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// BlockSourceInterface notifies subscribers of new chain heads.
+type BlockSourceInterface interface {
+	Subscribe() (<-chan uint64, func())
+	Latest() uint64
 }
@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// PoolGraphInterface is the in-memory universe of pools discovered from the
+// subgraphs. It is empty until the first crawl completes.
+type PoolGraphInterface interface {
+	Pool(address string) (*PoolData, bool)
+	Pools() []*PoolData
+	PoolsForToken(token string) []*PoolData
+	PoolsForPair(tokenA, tokenB string) []*PoolData
+	Size() int
+	UpdatedAt() time.Time
+}
+
+// PoolsRequest filters the pool universe. Token is a symbol or address; DEX
+// matches case-insensitively. Empty fields do not filter.
+type PoolsRequest struct {
+	Token string `json:"token,omitempty"`
+	DEX   string `json:"dex,omitempty"`
+}
+
+type PoolsResponse struct {
+	Pools     []PoolSummary `json:"pools"`
+	Count     int           `json:"count"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type PoolSummary struct {
+	Address      string `json:"address"`
+	DEX          string `json:"dex"`
+	Token0       string `json:"token0"`
+	Token1       string `json:"token1"`
+	Token0Symbol string `json:"token0_symbol"`
+	Token1Symbol string `json:"token1_symbol"`
+	Reserve0     string `json:"reserve0"`
+	Reserve1     string `json:"reserve1"`
+	TVL          string `json:"tvl"`
+}
+
+type PoolDetailRequest struct {
+	Address string `json:"address" validate:"required"`
+}
+
+// PoolDetail combines a pool's on-chain state at BlockNumber with what the
+// subgraph knows about it. PoolInfo reserves are in token units.
+type PoolDetail struct {
+	Address     string   `json:"address"`
+	DEX         string   `json:"dex,omitempty"`
+	PoolType    PoolType `json:"pool_type"`
+	Token0      Token    `json:"token0"`
+	Token1      Token    `json:"token1"`
+	BlockNumber uint64   `json:"block_number"`
+	PoolInfo    PoolInfo `json:"pool_info"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+type PriceRequest struct {
+	Base  string `json:"base" validate:"required"`
+	Quote string `json:"quote" validate:"required"`
+}
+
+// PriceResponse is the spot price of one whole Base in Quote at
+// BlockNumber. Route lists the token symbols the price was derived through;
+// it has an intermediate token when no direct pool exists.
+type PriceResponse struct {
+	BaseToken   string      `json:"base_token"`
+	QuoteToken  string      `json:"quote_token"`
+	Price       string      `json:"price"`
+	BlockNumber uint64      `json:"block_number"`
+	Route       []string    `json:"route"`
+	Pools       []PoolPrice `json:"pools"`
+}
+
+// PoolPrice is one pool's mid price for a leg of the route. Reserves are in
+// token units; the quote-side reserve is the pool's weight in the aggregate.
+type PoolPrice struct {
+	DEX          string `json:"dex"`
+	Pool         string `json:"pool"`
+	BaseToken    string `json:"base_token"`
+	QuoteToken   string `json:"quote_token"`
+	Price        string `json:"price"`
+	BaseReserve  string `json:"base_reserve"`
+	QuoteReserve string `json:"quote_reserve"`
+}
@@ -6,25 +6,42 @@ type QuoteRequest struct {
 	Amount string `json:"amount" validate:"required"`
 }
 
+// QuoteResponse reports the best quote net of gas. Gas is the fee market
+// gas costs were priced at; it and the net amounts are left out when gas
+// could not be priced, and the quotes are then ranked by gross output.
 type QuoteResponse struct {
-	FromToken  string     `json:"from_token"`
-	ToToken    string     `json:"to_token"`
-	FromAmount string     `json:"from_amount"`
-	ToAmount   string     `json:"to_amount"`
-	BestQuote  DEXQuote   `json:"best_quote"`
-	AllQuotes  []DEXQuote `json:"all_quotes"`
+	FromToken   string     `json:"from_token"`
+	ToToken     string     `json:"to_token"`
+	FromAmount  string     `json:"from_amount"`
+	ToAmount    string     `json:"to_amount"`
+	NetToAmount string     `json:"net_to_amount,omitempty"`
+	BestQuote   DEXQuote   `json:"best_quote"`
+	AllQuotes   []DEXQuote `json:"all_quotes"`
+	Gas         *GasInfo   `json:"gas,omitempty"`
 }
 
+// DEXQuote is one pool's quote. ToAmount is the gross output and
+// NetToAmount the output less the route's gas cost. GasCost is in wei.
 type DEXQuote struct {
-	DEX      string    `json:"dex"`
-	Pool     string    `json:"pool"`
-	ToAmount string    `json:"to_amount"`
-	Price    string    `json:"price,omitempty"`
-	PoolInfo *PoolInfo `json:"pool_info,omitempty"`
+	DEX         string    `json:"dex"`
+	Pool        string    `json:"pool"`
+	ToAmount    string    `json:"to_amount"`
+	NetToAmount string    `json:"net_to_amount,omitempty"`
+	GasEstimate uint64    `json:"gas_estimate,omitempty"`
+	GasCost     string    `json:"gas_cost,omitempty"`
+	Price       string    `json:"price,omitempty"`
+	PoolInfo    *PoolInfo `json:"pool_info,omitempty"`
 }
 
+// Sources of the TVL figure reported in PoolInfo.
+const (
+	TVLSourceSubgraph = "subgraph"
+	TVLSourceOnChain  = "onchain"
+)
+
 type PoolInfo struct {
 	TVL          string `json:"tvl"`
+	TVLSource    string `json:"tvl_source"`
 	Volume24h    string `json:"volume_24h"`
 	Fees24h      string `json:"fees_24h"`
 	Reserve0     string `json:"reserve0"`
@@ -0,0 +1,29 @@
+package domain
+
+// Message types exchanged over the quote streaming WebSocket.
+const (
+	StreamSubscribe    = "subscribe"
+	StreamUnsubscribe  = "unsubscribe"
+	StreamSubscribed   = "subscribed"
+	StreamUnsubscribed = "unsubscribed"
+	StreamQuote        = "quote"
+	StreamError        = "error"
+)
+
+// StreamRequest is sent by clients. ID is chosen by the client and echoed
+// on every message about that subscription.
+type StreamRequest struct {
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	Amount string `json:"amount,omitempty"`
+}
+
+type StreamMessage struct {
+	Type        string         `json:"type"`
+	ID          string         `json:"id,omitempty"`
+	BlockNumber uint64         `json:"block_number,omitempty"`
+	Quote       *QuoteResponse `json:"quote,omitempty"`
+	Error       *ErrorResponse `json:"error,omitempty"`
+}
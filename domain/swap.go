@@ -0,0 +1,44 @@
+package domain
+
+import "math/big"
+
+// SwapQuote is a quote with exact amounts in base units, for callers that
+// compute with the result rather than display it. Every pool was read at
+// BlockNumber. Best pays out the most net of gas; GasFees is nil, and the
+// ranking gross, when gas could not be priced.
+type SwapQuote struct {
+	From         TokenInfo
+	To           TokenInfo
+	AmountIn     *big.Int
+	AmountOut    *big.Int
+	NetAmountOut *big.Int
+	Best         PoolQuote
+	Quotes       []PoolQuote
+	GasFees      *GasFees
+	BlockNumber  uint64
+}
+
+// PoolQuote is what one pool pays out for the quoted amount. GasCost is in
+// wei; NetAmountOut is AmountOut less GasCost converted into the output
+// token, and nil when gas could not be priced.
+type PoolQuote struct {
+	DEX          string
+	Pool         string
+	AmountOut    *big.Int
+	NetAmountOut *big.Int
+	GasEstimate  uint64
+	GasCost      *big.Int
+	PoolInfo     *PoolInfo
+}
+
+// SwapEstimate is the output of swapping AmountIn of Src through Pool, in
+// base units, read at BlockNumber.
+type SwapEstimate struct {
+	Pool        string
+	PoolType    PoolType
+	Src         string
+	Dst         string
+	AmountIn    *big.Int
+	AmountOut   *big.Int
+	BlockNumber uint64
+}
@@ -1,10 +1,19 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrCircuitOpen is returned by graph sources while the circuit breaker for
+// their endpoint is open. Callers should fall back to on-chain data.
+var ErrCircuitOpen = errors.New("subgraph circuit breaker is open")
 
 type TheGraphServiceInterface interface {
 	GetPoolData(ctx context.Context, poolAddress string) (*PoolData, error)
 	GetPoolsByTokenPair(ctx context.Context, token0, token1 string) ([]*PoolData, error)
+	// GetAllPools lists every pool above the configured minimum TVL.
+	GetAllPools(ctx context.Context) ([]*PoolData, error)
 }
 
 type PoolData struct {
@@ -20,4 +29,5 @@ type PoolData struct {
 	Fees24hUSD   float64 `json:"fees24hUSD"`
 	Token0Symbol string  `json:"token0Symbol"`
 	Token1Symbol string  `json:"token1Symbol"`
+	DEX          string  `json:"dex"`
 }
@@ -0,0 +1,17 @@
+package domain
+
+type PriceTickRequest struct {
+	From string `json:"from" validate:"required"`
+	To   string `json:"to" validate:"required"`
+}
+
+// PriceTick is the mid price of From in To on the pool that currently gives
+// the best quote.
+type PriceTick struct {
+	FromToken   string `json:"from_token"`
+	ToToken     string `json:"to_token"`
+	Price       string `json:"price"`
+	BestDEX     string `json:"best_dex"`
+	Pool        string `json:"pool"`
+	BlockNumber uint64 `json:"block_number"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+type TokensRequest struct{}
+
+// Token is a symbol accepted by /quote together with its on-chain metadata.
+type Token struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Decimals uint8  `json:"decimals"`
+}
+
+type TokensResponse struct {
+	Tokens []Token `json:"tokens"`
+	Count  int     `json:"count"`
+}
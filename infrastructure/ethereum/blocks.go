@@ -0,0 +1,105 @@
+package ethereum
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type blockNumberSource interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// BlockWatcher polls the chain head and fans new block numbers out to
+// subscribers. Polling works against plain HTTP RPC endpoints, which cannot
+// push newHeads notifications.
+type BlockWatcher struct {
+	source   blockNumberSource
+	interval time.Duration
+
+	mu          sync.Mutex
+	subscribers map[chan uint64]struct{}
+	latest      uint64
+}
+
+func NewBlockWatcher(source blockNumberSource, interval time.Duration) *BlockWatcher {
+	return &BlockWatcher{
+		source:      source,
+		interval:    interval,
+		subscribers: make(map[chan uint64]struct{}),
+	}
+}
+
+// Subscribe returns a channel that receives each new block number. Slow
+// subscribers only ever see the most recent block, never a backlog.
+func (w *BlockWatcher) Subscribe() (<-chan uint64, func()) {
+	ch := make(chan uint64, 1)
+
+	w.mu.Lock()
+	w.subscribers[ch] = struct{}{}
+	w.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			w.mu.Lock()
+			delete(w.subscribers, ch)
+			w.mu.Unlock()
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// Latest returns the most recently observed block number, or zero before
+// the first poll succeeds.
+func (w *BlockWatcher) Latest() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.latest
+}
+
+// Run polls until ctx is done.
+func (w *BlockWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *BlockWatcher) poll(ctx context.Context) {
+	blockNumber, err := w.source.BlockNumber(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.WarnContext(ctx, "Failed to poll block number", "error", err)
+		}
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if blockNumber <= w.latest {
+		return
+	}
+	w.latest = blockNumber
+
+	for ch := range w.subscribers {
+		// Replace an undelivered block with the newer one.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- blockNumber
+	}
+}
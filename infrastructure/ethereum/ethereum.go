@@ -2,28 +2,41 @@ package ethereum
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"math/big"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/metrics"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/tracing"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type EthereumService struct {
 	client              *ethclient.Client
+	timeout             time.Duration
 	uniswapV2ABI        abi.ABI
 	uniswapV2FactoryABI abi.ABI
+	uniswapV3ABI        abi.ABI
 	erc20ABI            abi.ABI
 	tokenAddresses      map[string]string
 	tokenAddressesMu    sync.RWMutex
 	tokenInfoCache      map[string]*domain.TokenInfo
 	tokenInfoMu         sync.RWMutex
+	poolTypes           map[string]domain.PoolType
+	poolTypesMu         sync.RWMutex
+	dexFactories        map[string]string
 }
 
 const uniswapV2PairABI = `[
@@ -55,6 +68,13 @@ const uniswapV2PairABI = `[
 ]`
 
 const erc20ABI = `[
+	{
+		"inputs": [],
+		"name": "name",
+		"outputs": [{"internalType": "string", "name": "", "type": "string"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
 	{
 		"inputs": [],
 		"name": "symbol",
@@ -85,21 +105,33 @@ const uniswapV2FactoryABI = `[
 ]`
 
 // Known DEX factory addresses on Ethereum mainnet
-var dexFactories = map[string]string{
+var defaultDEXFactories = map[string]string{
 	"UniswapV2": "0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f",
 	"Sushiswap": "0xC0AEe478e3658e2610c5F7A4A2E1777cE9e4f2Ac",
 }
 
-func NewEthereumService(rpcURL string) (*EthereumService, error) {
-	client, err := ethclient.Dial(rpcURL)
+// NewEthereumService connects to the node at rpcURL. timeout bounds the
+// connection attempt and every later RPC call; zero means no limit.
+func NewEthereumService(rpcURL string, timeout time.Duration) (*EthereumService, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	client, err := ethclient.DialContext(ctx, rpcURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Ethereum: %w", err)
 	}
 
 	service := &EthereumService{
 		client:         client,
+		timeout:        timeout,
 		tokenAddresses: make(map[string]string),
 		tokenInfoCache: make(map[string]*domain.TokenInfo),
+		poolTypes:      make(map[string]domain.PoolType),
+		dexFactories:   maps.Clone(defaultDEXFactories),
 	}
 
 	if err := service.initABI(); err != nil {
@@ -109,6 +141,22 @@ func NewEthereumService(rpcURL string) (*EthereumService, error) {
 	return service, nil
 }
 
+// AddDEX makes FindPool and FindAllPools search the Uniswap V2-style
+// factory at factoryAddress under name, replacing any DEX of that name.
+// Call it before the service is shared.
+func (e *EthereumService) AddDEX(name, factoryAddress string) error {
+	if !common.IsHexAddress(factoryAddress) {
+		return fmt.Errorf("invalid factory address for %s: %s", name, factoryAddress)
+	}
+	e.dexFactories[name] = factoryAddress
+	return nil
+}
+
+// DEXes returns the names of the DEXes pools are searched on, sorted.
+func (e *EthereumService) DEXes() []string {
+	return slices.Sorted(maps.Keys(e.dexFactories))
+}
+
 func (e *EthereumService) initABI() error {
 	var err error
 
@@ -122,6 +170,11 @@ func (e *EthereumService) initABI() error {
 		return fmt.Errorf("failed to parse Uniswap V2 Factory ABI: %w", err)
 	}
 
+	e.uniswapV3ABI, err = abi.JSON(strings.NewReader(uniswapV3PoolABI))
+	if err != nil {
+		return fmt.Errorf("failed to parse Uniswap V3 pool ABI: %w", err)
+	}
+
 	e.erc20ABI, err = abi.JSON(strings.NewReader(erc20ABI))
 	if err != nil {
 		return fmt.Errorf("failed to parse ERC20 ABI: %w", err)
@@ -132,7 +185,7 @@ func (e *EthereumService) initABI() error {
 
 func (e *EthereumService) GetPoolReserves(ctx context.Context, poolAddress string) (*domain.PoolReserves, error) {
 	if !common.IsHexAddress(poolAddress) {
-		return nil, fmt.Errorf("invalid pool address: %s", poolAddress)
+		return nil, domain.NewError(domain.CodeInvalidInput, fmt.Sprintf("invalid pool address: %s", poolAddress), nil)
 	}
 
 	poolContract := common.HexToAddress(poolAddress)
@@ -153,10 +206,12 @@ func (e *EthereumService) GetPoolReserves(ctx context.Context, poolAddress strin
 	}
 
 	if token0Address == (common.Address{}) || token1Address == (common.Address{}) {
-		boundContract := bind.NewBoundContract(poolContract, e.uniswapV2ABI, e.client, e.client, e.client)
 
 		var token0Result []interface{}
-		if err := boundContract.Call(&bind.CallOpts{Context: ctx}, &token0Result, "token0"); err != nil {
+		if err := e.callBound(ctx, poolContract, e.uniswapV2ABI, &token0Result, "token0"); err != nil {
+			if errors.Is(err, bind.ErrNoCode) {
+				return nil, domain.NewError(domain.CodePoolNotFound, fmt.Sprintf("no contract at pool address %s", poolAddress), err)
+			}
 			return nil, fmt.Errorf("failed to call token0: %w", err)
 		}
 		if len(token0Result) > 0 {
@@ -168,7 +223,7 @@ func (e *EthereumService) GetPoolReserves(ctx context.Context, poolAddress strin
 		}
 
 		var token1Result []interface{}
-		if err := boundContract.Call(&bind.CallOpts{Context: ctx}, &token1Result, "token1"); err != nil {
+		if err := e.callBound(ctx, poolContract, e.uniswapV2ABI, &token1Result, "token1"); err != nil {
 			return nil, fmt.Errorf("failed to call token1: %w", err)
 		}
 		if len(token1Result) > 0 {
@@ -199,7 +254,7 @@ func (e *EthereumService) GetPoolReserves(ctx context.Context, poolAddress strin
 		return nil, fmt.Errorf("failed to unpack reserves data: %w", err)
 	}
 
-	blockNumber, err := e.client.BlockNumber(ctx)
+	blockNumber, err := e.BlockNumber(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current block number: %w", err)
 	}
@@ -215,7 +270,7 @@ func (e *EthereumService) GetPoolReserves(ctx context.Context, poolAddress strin
 
 func (e *EthereumService) GetTokenInfo(ctx context.Context, tokenAddress string) (*domain.TokenInfo, error) {
 	if !common.IsHexAddress(tokenAddress) {
-		return nil, fmt.Errorf("invalid token address: %s", tokenAddress)
+		return nil, domain.NewError(domain.CodeInvalidInput, fmt.Sprintf("invalid token address: %s", tokenAddress), nil)
 	}
 
 	e.tokenInfoMu.RLock()
@@ -226,11 +281,10 @@ func (e *EthereumService) GetTokenInfo(ctx context.Context, tokenAddress string)
 	e.tokenInfoMu.RUnlock()
 
 	tokenContract := common.HexToAddress(tokenAddress)
-	boundContract := bind.NewBoundContract(tokenContract, e.erc20ABI, e.client, e.client, e.client)
 
 	var symbol string
 	var symbolResult []interface{}
-	if err := boundContract.Call(&bind.CallOpts{Context: ctx}, &symbolResult, "symbol"); err != nil {
+	if err := e.callBound(ctx, tokenContract, e.erc20ABI, &symbolResult, "symbol"); err != nil {
 		return nil, fmt.Errorf("failed to call symbol: %w", err)
 	}
 	if len(symbolResult) > 0 {
@@ -243,7 +297,7 @@ func (e *EthereumService) GetTokenInfo(ctx context.Context, tokenAddress string)
 
 	var decimals uint8
 	var decimalsResult []interface{}
-	if err := boundContract.Call(&bind.CallOpts{Context: ctx}, &decimalsResult, "decimals"); err != nil {
+	if err := e.callBound(ctx, tokenContract, e.erc20ABI, &decimalsResult, "decimals"); err != nil {
 		return nil, fmt.Errorf("failed to call decimals: %w", err)
 	}
 
@@ -266,9 +320,18 @@ func (e *EthereumService) GetTokenInfo(ctx context.Context, tokenAddress string)
 		}
 	}
 
+	// name is optional in ERC-20 and some early tokens return bytes32, so a
+	// failed call leaves the name empty instead of failing the lookup.
+	var name string
+	var nameResult []interface{}
+	if err := e.callBound(ctx, tokenContract, e.erc20ABI, &nameResult, "name"); err == nil && len(nameResult) > 0 {
+		name, _ = nameResult[0].(string)
+	}
+
 	tokenInfo := &domain.TokenInfo{
 		Address:  tokenAddress,
 		Symbol:   symbol,
+		Name:     name,
 		Decimals: decimals,
 	}
 
@@ -279,22 +342,97 @@ func (e *EthereumService) GetTokenInfo(ctx context.Context, tokenAddress string)
 	return tokenInfo, nil
 }
 
+// BlockNumber returns the block reads are pinned to by the context, or the
+// current chain head when none is pinned.
+func (e *EthereumService) BlockNumber(ctx context.Context) (uint64, error) {
+	if blockNumber, ok := domain.BlockNumberFromContext(ctx); ok {
+		return blockNumber, nil
+	}
+	ctx, done := e.startCall(ctx, "eth_blockNumber", common.Address{})
+	blockNumber, err := e.client.BlockNumber(ctx)
+	done(err)
+	return blockNumber, err
+}
+
+// HeadBlock returns the number and timestamp of the latest block, ignoring
+// any block pinned by the context.
+func (e *EthereumService) HeadBlock(ctx context.Context) (domain.BlockHeader, error) {
+	ctx, done := e.startCall(ctx, "eth_getBlockByNumber", common.Address{})
+	header, err := e.client.HeaderByNumber(ctx, nil)
+	done(err)
+	if err != nil {
+		return domain.BlockHeader{}, err
+	}
+
+	return domain.BlockHeader{
+		Number: header.Number.Uint64(),
+		Time:   time.Unix(int64(header.Time), 0),
+	}, nil
+}
+
+func (e *EthereumService) callOpts(ctx context.Context) *bind.CallOpts {
+	return &bind.CallOpts{Context: ctx, BlockNumber: pinnedBlock(ctx)}
+}
+
+// pinnedBlock returns the block pinned by the context, or nil for latest.
+func pinnedBlock(ctx context.Context) *big.Int {
+	if blockNumber, ok := domain.BlockNumberFromContext(ctx); ok {
+		return new(big.Int).SetUint64(blockNumber)
+	}
+	return nil
+}
+
+// callBound calls method through a bound contract, which packs params and
+// unpacks the outputs into results.
+func (e *EthereumService) callBound(ctx context.Context, contract common.Address, parsedABI abi.ABI, results *[]interface{}, method string, params ...interface{}) error {
+	ctx, done := e.startCall(ctx, method, contract)
+	boundContract := bind.NewBoundContract(contract, parsedABI, e.client, e.client, e.client)
+	err := boundContract.Call(e.callOpts(ctx), results, method, params...)
+	done(err)
+	return err
+}
+
+// startCall opens a span for one RPC call and bounds it by the configured
+// timeout; the returned func records its outcome in the span and the RPC
+// metrics and releases the timeout.
+func (e *EthereumService) startCall(ctx context.Context, method string, contract common.Address) (context.Context, func(error)) {
+	start := time.Now()
+	attrs := []attribute.KeyValue{attribute.String("rpc.method", method)}
+	if contract != (common.Address{}) {
+		attrs = append(attrs, attribute.String("eth.contract", contract.Hex()))
+	}
+	ctx, span := tracing.Start(ctx, "eth "+method, attrs...)
+
+	cancel := context.CancelFunc(func() {})
+	if e.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+	}
+
+	return ctx, func(err error) {
+		cancel()
+		metrics.ObserveRPC(method, start, err)
+		tracing.End(span, err)
+	}
+}
+
 func (e *EthereumService) callContract(ctx context.Context, contract common.Address, parsedABI abi.ABI, method string) ([]byte, error) {
 	data, err := parsedABI.Pack(method)
 	if err != nil {
 		return nil, fmt.Errorf("failed to pack method %s: %w", method, err)
 	}
 
+	ctx, done := e.startCall(ctx, method, contract)
 	result, err := e.client.CallContract(ctx, ethereum.CallMsg{
 		To:   &contract,
 		Data: data,
-	}, nil)
+	}, pinnedBlock(ctx))
+	done(err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call contract method %s: %w", method, err)
 	}
 
 	if len(result) == 0 {
-		return nil, fmt.Errorf("empty result from contract method %s", method)
+		return nil, fmt.Errorf("%w from contract method %s", errEmptyResult, method)
 	}
 
 	return result, nil
@@ -302,7 +440,7 @@ func (e *EthereumService) callContract(ctx context.Context, contract common.Addr
 
 // FindPool finds a pool address for a token pair on a specific DEX
 func (e *EthereumService) FindPool(ctx context.Context, dexName, tokenA, tokenB string) (string, error) {
-	factoryAddress, ok := dexFactories[dexName]
+	factoryAddress, ok := e.dexFactories[dexName]
 	if !ok {
 		return "", fmt.Errorf("unknown DEX: %s", dexName)
 	}
@@ -326,10 +464,9 @@ func (e *EthereumService) FindPool(ctx context.Context, dexName, tokenA, tokenB
 	}
 
 	factoryContract := common.HexToAddress(factoryAddress)
-	boundContract := bind.NewBoundContract(factoryContract, e.uniswapV2FactoryABI, e.client, e.client, e.client)
 
 	var pairResult []interface{}
-	if err := boundContract.Call(&bind.CallOpts{Context: ctx}, &pairResult, "getPair", token0, token1); err != nil {
+	if err := e.callBound(ctx, factoryContract, e.uniswapV2FactoryABI, &pairResult, "getPair", token0, token1); err != nil {
 		return "", fmt.Errorf("failed to call getPair: %w", err)
 	}
 
@@ -365,7 +502,7 @@ func (e *EthereumService) GetQuoteForPool(ctx context.Context, poolAddress, toke
 		reserveIn = poolReserves.Reserve1
 		reserveOut = poolReserves.Reserve0
 	} else {
-		return nil, fmt.Errorf("token %s not found in pool", tokenIn)
+		return nil, domain.NewError(domain.CodeInvalidInput, fmt.Sprintf("token %s not found in pool", tokenIn), nil)
 	}
 
 	// Use Uniswap V2 formula: amountOut = (amountIn * 997 * reserveOut) / (reserveIn * 1000 + amountIn * 997)
@@ -387,10 +524,11 @@ func (e *EthereumService) GetQuoteForPool(ctx context.Context, poolAddress, toke
 func (e *EthereumService) FindAllPools(ctx context.Context, tokenA, tokenB string) (map[string]string, error) {
 	pools := make(map[string]string)
 
-	for dexName := range dexFactories {
+	for dexName := range e.dexFactories {
 		poolAddress, err := e.FindPool(ctx, dexName, tokenA, tokenB)
 		if err != nil {
-			// Pool doesn't exist on this DEX, skip
+			// Usually the pair simply does not exist on this DEX.
+			slog.DebugContext(ctx, "Skipping DEX", "reason", err, "dex", dexName, "token_a", tokenA, "token_b", tokenB)
 			continue
 		}
 		pools[dexName] = poolAddress
@@ -0,0 +1,18 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FeeHistory reads eth_feeHistory for the blockCount blocks up to and
+// including newest, with the priority fees paid at each percentile.
+func (e *EthereumService) FeeHistory(ctx context.Context, blockCount, newest uint64, percentiles []float64) (*ethereum.FeeHistory, error) {
+	ctx, done := e.startCall(ctx, "eth_feeHistory", common.Address{})
+	history, err := e.client.FeeHistory(ctx, blockCount, new(big.Int).SetUint64(newest), percentiles)
+	done(err)
+	return history, err
+}
@@ -0,0 +1,233 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/ethereum/go-ethereum"
+	"golang.org/x/sync/singleflight"
+)
+
+// FeeHistorySource reads the chain head and its recent fee history, as
+// EthereumService does.
+type FeeHistorySource interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	FeeHistory(ctx context.Context, blockCount, newest uint64, percentiles []float64) (*ethereum.FeeHistory, error)
+}
+
+type latestBlockSource interface {
+	Latest() uint64
+}
+
+// cachedSuggestionBlocks is how many blocks the oracle keeps suggestions
+// for, so requests pinned to recent blocks do not refetch fee history.
+const cachedSuggestionBlocks = 32
+
+// GasOracleOptions sets the sliding window of the oracle and the priority
+// fee percentile of each tier.
+type GasOracleOptions struct {
+	// BlockCount is how many recent blocks the suggestion is based on.
+	BlockCount         int
+	SlowPercentile     float64
+	StandardPercentile float64
+	FastPercentile     float64
+	// FetchTimeout bounds a fee history read. The read is shared by every
+	// concurrent caller, so it runs detached from the one that started it.
+	// Zero means no limit.
+	FetchTimeout time.Duration
+}
+
+// DefaultGasOracleOptions looks at the last 20 blocks and suggests the
+// 10th, 50th and 90th percentile priority fees, reading fee history for at
+// most 10 seconds.
+func DefaultGasOracleOptions() GasOracleOptions {
+	return GasOracleOptions{
+		BlockCount:         20,
+		SlowPercentile:     10,
+		StandardPercentile: 50,
+		FastPercentile:     90,
+		FetchTimeout:       10 * time.Second,
+	}
+}
+
+// GasOracle suggests EIP-1559 fees from eth_feeHistory. Each tier's
+// priority fee is the median, over the window, of the priority fee paid at
+// its percentile in each block; its max fee leaves room for the base fee
+// to double. Suggestions are cached per block for the most recent blocks.
+type GasOracle struct {
+	source  FeeHistorySource
+	heads   latestBlockSource
+	options GasOracleOptions
+
+	group       singleflight.Group
+	mu          sync.Mutex
+	suggestions map[uint64]*domain.GasSuggestion
+}
+
+// NewGasOracle builds an oracle over source. heads, when not nil, supplies
+// the chain head without an RPC call, e.g. a running BlockWatcher.
+func NewGasOracle(source FeeHistorySource, heads latestBlockSource, options GasOracleOptions) *GasOracle {
+	return &GasOracle{
+		source:      source,
+		heads:       heads,
+		options:     options,
+		suggestions: make(map[uint64]*domain.GasSuggestion),
+	}
+}
+
+// GasSuggestion returns the fees for the block ctx is pinned to, or for the
+// chain head.
+func (o *GasOracle) GasSuggestion(ctx context.Context) (*domain.GasSuggestion, error) {
+	head, err := o.head(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	cached, ok := o.suggestions[head]
+	o.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	// The shared read must not fail every waiter when the caller that
+	// started it goes away, so it runs on a detached context; each caller
+	// still honours its own deadline below.
+	result := o.group.DoChan(strconv.FormatUint(head, 10), func() (interface{}, error) {
+		fetchCtx, cancel := o.detach(ctx)
+		defer cancel()
+
+		suggestion, err := o.suggest(fetchCtx, head)
+		if err != nil {
+			return nil, err
+		}
+
+		o.store(suggestion)
+
+		return suggestion, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-result:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*domain.GasSuggestion), nil
+	}
+}
+
+func (o *GasOracle) detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = context.WithoutCancel(ctx)
+	if o.options.FetchTimeout > 0 {
+		return context.WithTimeout(ctx, o.options.FetchTimeout)
+	}
+	return ctx, func() {}
+}
+
+// GasFees prices quotes at the standard tier.
+func (o *GasOracle) GasFees(ctx context.Context) (*domain.GasFees, error) {
+	suggestion, err := o.GasSuggestion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.GasFees{
+		BaseFee:     suggestion.BaseFee,
+		PriorityFee: suggestion.Standard.MaxPriorityFeePerGas,
+		BlockNumber: suggestion.BlockNumber,
+	}, nil
+}
+
+// store caches suggestion and evicts the oldest block beyond the limit. A
+// request pinned to an older block therefore never evicts the head.
+func (o *GasOracle) store(suggestion *domain.GasSuggestion) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.suggestions[suggestion.BlockNumber] = suggestion
+	if len(o.suggestions) <= cachedSuggestionBlocks {
+		return
+	}
+	oldest := suggestion.BlockNumber
+	for blockNumber := range o.suggestions {
+		oldest = min(oldest, blockNumber)
+	}
+	delete(o.suggestions, oldest)
+}
+
+func (o *GasOracle) head(ctx context.Context) (uint64, error) {
+	if blockNumber, ok := domain.BlockNumberFromContext(ctx); ok {
+		return blockNumber, nil
+	}
+	if o.heads != nil {
+		if latest := o.heads.Latest(); latest > 0 {
+			return latest, nil
+		}
+	}
+	return o.source.BlockNumber(ctx)
+}
+
+func (o *GasOracle) suggest(ctx context.Context, head uint64) (*domain.GasSuggestion, error) {
+	percentiles := []float64{o.options.SlowPercentile, o.options.StandardPercentile, o.options.FastPercentile}
+	history, err := o.source.FeeHistory(ctx, uint64(o.options.BlockCount), head, percentiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fee history: %w", err)
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, errors.New("node returned no fee history")
+	}
+
+	// The last base fee is the one of the block after newest.
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+	if baseFee == nil || baseFee.Sign() == 0 {
+		return nil, errors.New("chain has no EIP-1559 base fee")
+	}
+
+	tiers := make([]domain.GasTier, len(percentiles))
+	for i, percentile := range percentiles {
+		priorityFee := medianReward(history, i)
+		tiers[i] = domain.GasTier{
+			Percentile:           percentile,
+			MaxPriorityFeePerGas: priorityFee,
+			MaxFeePerGas:         new(big.Int).Add(new(big.Int).Lsh(baseFee, 1), priorityFee),
+		}
+	}
+
+	return &domain.GasSuggestion{
+		BlockNumber: head,
+		BlockCount:  len(history.Reward),
+		BaseFee:     new(big.Int).Set(baseFee),
+		Slow:        tiers[0],
+		Standard:    tiers[1],
+		Fast:        tiers[2],
+	}, nil
+}
+
+// medianReward is the median priority fee paid at the index-th percentile
+// across the window. Empty blocks report zero rewards that say nothing
+// about demand, so they are left out.
+func medianReward(history *ethereum.FeeHistory, index int) *big.Int {
+	rewards := make([]*big.Int, 0, len(history.Reward))
+	for block, blockRewards := range history.Reward {
+		if block < len(history.GasUsedRatio) && history.GasUsedRatio[block] == 0 {
+			continue
+		}
+		if index < len(blockRewards) && blockRewards[index] != nil {
+			rewards = append(rewards, blockRewards[index])
+		}
+	}
+	if len(rewards) == 0 {
+		return new(big.Int)
+	}
+
+	slices.SortFunc(rewards, (*big.Int).Cmp)
+	return new(big.Int).Set(rewards[len(rewards)/2])
+}
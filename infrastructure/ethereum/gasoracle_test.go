@@ -0,0 +1,301 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/ethereum/go-ethereum"
+)
+
+// fakeFeeHistory serves history for any newest block and counts the reads.
+// When release is set, each read waits for it to close or for its context
+// to be done.
+type fakeFeeHistory struct {
+	head    uint64
+	history *ethereum.FeeHistory
+	err     error
+	release chan struct{}
+
+	calls atomic.Int32
+}
+
+func (f *fakeFeeHistory) BlockNumber(context.Context) (uint64, error) {
+	return f.head, nil
+}
+
+func (f *fakeFeeHistory) FeeHistory(ctx context.Context, blockCount, newest uint64, _ []float64) (*ethereum.FeeHistory, error) {
+	f.calls.Add(1)
+	if f.release != nil {
+		select {
+		case <-f.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	history := *f.history
+	history.OldestBlock = new(big.Int).SetUint64(newest - blockCount + 1)
+	return &history, nil
+}
+
+type fixedHead uint64
+
+func (h fixedHead) Latest() uint64 {
+	return uint64(h)
+}
+
+func gwei(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), big.NewInt(1e9))
+}
+
+func rewards(slow, standard, fast int64) []*big.Int {
+	return []*big.Int{gwei(slow), gwei(standard), gwei(fast)}
+}
+
+// threeBlocks is a window of two busy blocks around an empty one. The last
+// base fee is the next block's.
+var threeBlocks = &ethereum.FeeHistory{
+	Reward:       [][]*big.Int{rewards(1, 2, 5), rewards(0, 0, 0), rewards(3, 4, 9)},
+	BaseFee:      []*big.Int{gwei(10), gwei(11), gwei(12), gwei(20)},
+	GasUsedRatio: []float64{0.5, 0, 0.9},
+}
+
+func TestGasSuggestion(t *testing.T) {
+	source := &fakeFeeHistory{head: 100, history: threeBlocks}
+	oracle := NewGasOracle(source, nil, GasOracleOptions{BlockCount: 3, SlowPercentile: 10, StandardPercentile: 50, FastPercentile: 90})
+
+	suggestion, err := oracle.GasSuggestion(context.Background())
+	if err != nil {
+		t.Fatalf("GasSuggestion: %v", err)
+	}
+
+	if suggestion.BlockNumber != 100 || suggestion.BlockCount != 3 {
+		t.Errorf("block = %d, count = %d, want 100 and 3", suggestion.BlockNumber, suggestion.BlockCount)
+	}
+	if suggestion.BaseFee.Cmp(gwei(20)) != 0 {
+		t.Errorf("base fee = %s, want the last entry %s", suggestion.BaseFee, gwei(20))
+	}
+
+	// The empty block's zero rewards are left out, so each median is the
+	// larger of the two busy blocks' rewards.
+	tiers := []struct {
+		name        string
+		tier        domain.GasTier
+		percentile  float64
+		priorityFee *big.Int
+	}{
+		{"slow", suggestion.Slow, 10, gwei(3)},
+		{"standard", suggestion.Standard, 50, gwei(4)},
+		{"fast", suggestion.Fast, 90, gwei(9)},
+	}
+	for _, tt := range tiers {
+		if tt.tier.Percentile != tt.percentile {
+			t.Errorf("%s percentile = %v, want %v", tt.name, tt.tier.Percentile, tt.percentile)
+		}
+		if tt.tier.MaxPriorityFeePerGas.Cmp(tt.priorityFee) != 0 {
+			t.Errorf("%s priority fee = %s, want %s", tt.name, tt.tier.MaxPriorityFeePerGas, tt.priorityFee)
+		}
+		maxFee := new(big.Int).Add(gwei(40), tt.priorityFee)
+		if tt.tier.MaxFeePerGas.Cmp(maxFee) != 0 {
+			t.Errorf("%s max fee = %s, want twice the base fee plus the tip, %s", tt.name, tt.tier.MaxFeePerGas, maxFee)
+		}
+	}
+
+	fees, err := oracle.GasFees(context.Background())
+	if err != nil {
+		t.Fatalf("GasFees: %v", err)
+	}
+	if fees.BaseFee.Cmp(gwei(20)) != 0 || fees.PriorityFee.Cmp(gwei(4)) != 0 || fees.BlockNumber != 100 {
+		t.Errorf("fees = %+v, want the standard tier at block 100", fees)
+	}
+}
+
+func TestMedianReward(t *testing.T) {
+	tests := []struct {
+		name    string
+		history *ethereum.FeeHistory
+		want    *big.Int
+	}{
+		{
+			name: "odd number of blocks",
+			history: &ethereum.FeeHistory{
+				Reward:       [][]*big.Int{{gwei(5)}, {gwei(1)}, {gwei(3)}},
+				GasUsedRatio: []float64{0.5, 0.5, 0.5},
+			},
+			want: gwei(3),
+		},
+		{
+			name: "empty blocks are left out",
+			history: &ethereum.FeeHistory{
+				Reward:       [][]*big.Int{{gwei(0)}, {gwei(0)}, {gwei(0)}, {gwei(7)}},
+				GasUsedRatio: []float64{0, 0, 0, 0.5},
+			},
+			want: gwei(7),
+		},
+		{
+			name: "only empty blocks",
+			history: &ethereum.FeeHistory{
+				Reward:       [][]*big.Int{{gwei(0)}, {gwei(0)}},
+				GasUsedRatio: []float64{0, 0},
+			},
+			want: new(big.Int),
+		},
+		{
+			name:    "no rewards",
+			history: &ethereum.FeeHistory{},
+			want:    new(big.Int),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := medianReward(tt.history, 0); got.Cmp(tt.want) != 0 {
+				t.Errorf("median = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGasSuggestionErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source *fakeFeeHistory
+	}{
+		{
+			name:   "fee history unavailable",
+			source: &fakeFeeHistory{head: 100, err: errors.New("method not found")},
+		},
+		{
+			name:   "no base fees",
+			source: &fakeFeeHistory{head: 100, history: &ethereum.FeeHistory{}},
+		},
+		{
+			name:   "pre-London chain",
+			source: &fakeFeeHistory{head: 100, history: &ethereum.FeeHistory{BaseFee: []*big.Int{new(big.Int)}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oracle := NewGasOracle(tt.source, nil, DefaultGasOracleOptions())
+			if suggestion, err := oracle.GasSuggestion(context.Background()); err == nil {
+				t.Errorf("suggestion = %+v, want an error", suggestion)
+			}
+		})
+	}
+}
+
+func TestGasSuggestionCachedPerBlock(t *testing.T) {
+	source := &fakeFeeHistory{head: 1, history: threeBlocks}
+	oracle := NewGasOracle(source, fixedHead(1000), DefaultGasOracleOptions())
+
+	suggest := func(ctx context.Context) *domain.GasSuggestion {
+		t.Helper()
+		suggestion, err := oracle.GasSuggestion(ctx)
+		if err != nil {
+			t.Fatalf("GasSuggestion: %v", err)
+		}
+		return suggestion
+	}
+
+	head := suggest(context.Background())
+	if head.BlockNumber != 1000 {
+		t.Errorf("block = %d, want the watched head 1000", head.BlockNumber)
+	}
+	if suggest(context.Background()) != head {
+		t.Error("the head was read again")
+	}
+
+	// A pinned request for an older block does not evict the head, and
+	// every recent block stays cached.
+	pinned := suggest(domain.WithBlockNumber(context.Background(), 999))
+	if pinned.BlockNumber != 999 {
+		t.Errorf("block = %d, want the pinned block 999", pinned.BlockNumber)
+	}
+	if suggest(context.Background()) != head || suggest(domain.WithBlockNumber(context.Background(), 999)) != pinned {
+		t.Error("a cached block was read again")
+	}
+	if calls := source.calls.Load(); calls != 2 {
+		t.Errorf("read fee history %d times, want 2", calls)
+	}
+
+	// Filling the cache evicts the oldest block first.
+	for blockNumber := uint64(1001); blockNumber < 1001+cachedSuggestionBlocks-1; blockNumber++ {
+		suggest(domain.WithBlockNumber(context.Background(), blockNumber))
+	}
+	calls := source.calls.Load()
+	suggest(context.Background())
+	if source.calls.Load() != calls {
+		t.Error("the head was evicted before the oldest block")
+	}
+	suggest(domain.WithBlockNumber(context.Background(), 999))
+	if source.calls.Load() != calls+1 {
+		t.Error("the oldest block was not evicted")
+	}
+}
+
+func TestGasSuggestionSharesConcurrentReads(t *testing.T) {
+	source := &fakeFeeHistory{head: 100, history: threeBlocks, release: make(chan struct{})}
+	oracle := NewGasOracle(source, nil, DefaultGasOracleOptions())
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := oracle.GasSuggestion(context.Background()); err != nil {
+				t.Errorf("GasSuggestion: %v", err)
+			}
+		}()
+	}
+
+	// Let every caller reach the in-flight read before it completes.
+	time.Sleep(50 * time.Millisecond)
+	close(source.release)
+	wg.Wait()
+
+	if calls := source.calls.Load(); calls != 1 {
+		t.Errorf("read fee history %d times, want 1", calls)
+	}
+}
+
+func TestGasSuggestionOutlivesTheCallerThatStartedIt(t *testing.T) {
+	source := &fakeFeeHistory{head: 100, history: threeBlocks, release: make(chan struct{})}
+	oracle := NewGasOracle(source, nil, DefaultGasOracleOptions())
+
+	first, cancel := context.WithCancel(context.Background())
+	started := make(chan error, 1)
+	go func() {
+		_, err := oracle.GasSuggestion(first)
+		started <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := oracle.GasSuggestion(context.Background())
+		waiter <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-started; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller error = %v, want context.Canceled", err)
+	}
+
+	close(source.release)
+	if err := <-waiter; err != nil {
+		t.Errorf("waiter error = %v, want the shared read's suggestion", err)
+	}
+	if calls := source.calls.Load(); calls != 1 {
+		t.Errorf("read fee history %d times, want 1", calls)
+	}
+}
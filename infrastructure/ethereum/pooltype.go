@@ -0,0 +1,148 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const uniswapV3PoolABI = `[
+	{
+		"inputs": [],
+		"name": "slot0",
+		"outputs": [
+			{"internalType": "uint160", "name": "sqrtPriceX96", "type": "uint160"},
+			{"internalType": "int24", "name": "tick", "type": "int24"},
+			{"internalType": "uint16", "name": "observationIndex", "type": "uint16"},
+			{"internalType": "uint16", "name": "observationCardinality", "type": "uint16"},
+			{"internalType": "uint16", "name": "observationCardinalityNext", "type": "uint16"},
+			{"internalType": "uint8", "name": "feeProtocol", "type": "uint8"},
+			{"internalType": "bool", "name": "unlocked", "type": "bool"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "liquidity",
+		"outputs": [{"internalType": "uint128", "name": "", "type": "uint128"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "fee",
+		"outputs": [{"internalType": "uint24", "name": "", "type": "uint24"}],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+var (
+	errNoPoolType  = errors.New("pool type not detected")
+	errEmptyResult = errors.New("empty result")
+)
+
+// DetectPoolType probes which AMM interface the contract at poolAddress
+// answers to. A pool type never changes, so results are cached per address;
+// a probe cut short by a failed RPC call is not cached.
+func (e *EthereumService) DetectPoolType(ctx context.Context, poolAddress string) (domain.PoolType, error) {
+	if !common.IsHexAddress(poolAddress) {
+		return domain.PoolTypeUnknown, domain.NewError(domain.CodeInvalidInput, fmt.Sprintf("invalid pool address: %s", poolAddress), nil)
+	}
+
+	key := strings.ToLower(poolAddress)
+
+	e.poolTypesMu.RLock()
+	poolType, ok := e.poolTypes[key]
+	e.poolTypesMu.RUnlock()
+	if ok {
+		return poolType, nil
+	}
+
+	contract := common.HexToAddress(poolAddress)
+
+	codeCtx, done := e.startCall(ctx, "eth_getCode", contract)
+	code, err := e.client.CodeAt(codeCtx, contract, pinnedBlock(ctx))
+	done(err)
+	if err != nil {
+		return domain.PoolTypeUnknown, fmt.Errorf("failed to get pool code: %w", err)
+	}
+	if len(code) == 0 {
+		return domain.PoolTypeUnknown, domain.NewError(domain.CodePoolNotFound, fmt.Sprintf("no contract at pool address %s", poolAddress), nil)
+	}
+
+	poolType, err = e.probePoolType(ctx, contract)
+	if err != nil && !errors.Is(err, errNoPoolType) {
+		return domain.PoolTypeUnknown, err
+	}
+
+	e.poolTypesMu.Lock()
+	e.poolTypes[key] = poolType
+	e.poolTypesMu.Unlock()
+
+	return poolType, nil
+}
+
+func (e *EthereumService) probePoolType(ctx context.Context, contract common.Address) (domain.PoolType, error) {
+	probes := []struct {
+		poolType domain.PoolType
+		abi      abi.ABI
+		methods  []string
+	}{
+		{domain.PoolTypeUniswapV2, e.uniswapV2ABI, []string{"token0", "token1", "getReserves"}},
+		{domain.PoolTypeUniswapV3, e.uniswapV3ABI, []string{"slot0", "liquidity", "fee"}},
+	}
+
+	for _, probe := range probes {
+		matched := true
+		for _, method := range probe.methods {
+			data, err := e.callContract(ctx, contract, probe.abi, method)
+			if ctx.Err() != nil {
+				return domain.PoolTypeUnknown, ctx.Err()
+			}
+			// A revert or a return value that does not decode as the
+			// expected outputs means the contract lacks this interface.
+			// Any other failure says nothing about the contract.
+			if err != nil {
+				if !isExecutionError(err) {
+					return domain.PoolTypeUnknown, fmt.Errorf("failed to probe pool type: %w", err)
+				}
+				matched = false
+				break
+			}
+			if _, err := probe.abi.Unpack(method, data); err != nil {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return probe.poolType, nil
+		}
+	}
+
+	return domain.PoolTypeUnknown, errNoPoolType
+}
+
+// isExecutionError reports whether err is the contract call itself failing,
+// as opposed to the node or the connection to it.
+func isExecutionError(err error) bool {
+	if errors.Is(err, errEmptyResult) {
+		return true
+	}
+
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	// Code 3 carries revert data; nodes report reverts without data and
+	// invalid opcodes under the generic server error code.
+	message := strings.ToLower(rpcErr.Error())
+	return rpcErr.ErrorCode() == 3 || strings.Contains(message, "revert") || strings.Contains(message, "invalid opcode")
+}
@@ -1,6 +1,12 @@
 package ethereum
 
-var tokenAddresses = map[string]string{
+import (
+	"maps"
+	"sort"
+	"sync"
+)
+
+var builtinTokens = map[string]string{
 	"ETH":  "0x0000000000000000000000000000000000000000",
 	"WETH": "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
 	"USDC": "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
@@ -10,17 +16,52 @@ var tokenAddresses = map[string]string{
 	"UNI":  "0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984",
 }
 
-// GetTokenAddress returns the token address for a given symbol
-// Returns empty string if token is not found
-func GetTokenAddress(symbol string) string {
-	if addr, ok := tokenAddresses[symbol]; ok {
-		return addr
+// NativeTokenSymbol is the chain's native asset. It has no contract; quotes
+// trade it through WETH.
+const NativeTokenSymbol = "ETH"
+
+// TokenRegistry maps token symbols onto addresses: the built-in mainnet
+// tokens plus configured ones, which may override built-in addresses.
+type TokenRegistry struct {
+	mu        sync.RWMutex
+	addresses map[string]string
+}
+
+func NewTokenRegistry(tokens map[string]string) *TokenRegistry {
+	r := &TokenRegistry{}
+	r.Set(tokens)
+	return r
+}
+
+// Set replaces the configured tokens. It is safe to call while requests
+// are served.
+func (r *TokenRegistry) Set(tokens map[string]string) {
+	addresses := maps.Clone(builtinTokens)
+	maps.Copy(addresses, tokens)
+
+	r.mu.Lock()
+	r.addresses = addresses
+	r.mu.Unlock()
+}
+
+// Symbols returns every symbol in the registry, sorted.
+func (r *TokenRegistry) Symbols() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	symbols := make([]string, 0, len(r.addresses))
+	for symbol := range r.addresses {
+		symbols = append(symbols, symbol)
 	}
-	return ""
+	sort.Strings(symbols)
+	return symbols
 }
 
-// IsValidTokenSymbol checks if a token symbol is known
-func IsValidTokenSymbol(symbol string) bool {
-	_, ok := tokenAddresses[symbol]
-	return ok
+// Address returns the token address for a given symbol
+// Returns empty string if token is not found
+func (r *TokenRegistry) Address(symbol string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.addresses[symbol]
 }
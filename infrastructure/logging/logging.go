@@ -0,0 +1,101 @@
+// Package logging configures the process-wide slog logger. Records logged
+// with a request context carry its request and trace IDs.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Formats accepted by Setup.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+type Options struct {
+	// Level is one of debug, info, warn and error.
+	Level string
+	// Format is FormatText or FormatJSON.
+	Format string
+}
+
+// level is shared by every logger Setup builds so SetLevel applies at once.
+var level slog.LevelVar
+
+// Setup installs a logger writing to w as the slog default, which also
+// routes the standard log package through it.
+func Setup(w io.Writer, options Options) (*slog.Logger, error) {
+	if err := SetLevel(options.Level); err != nil {
+		return nil, err
+	}
+
+	handlerOptions := &slog.HandlerOptions{Level: &level}
+
+	var handler slog.Handler
+	switch strings.ToLower(options.Format) {
+	case "", FormatText:
+		handler = slog.NewTextHandler(w, handlerOptions)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, handlerOptions)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", options.Format)
+	}
+
+	logger := slog.New(contextHandler{handler})
+	slog.SetDefault(logger)
+
+	return logger, nil
+}
+
+// SetLevel changes the minimum level of the installed logger.
+func SetLevel(name string) error {
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(name)); err != nil {
+		return fmt.Errorf("invalid log level %q: %w", name, err)
+	}
+	level.Set(parsed)
+	return nil
+}
+
+type requestIDKey struct{}
+
+// WithRequestID attaches the request ID to ctx for every record logged
+// with it.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// contextHandler adds request_id and trace_id from the record's context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		record.AddAttrs(slog.String("trace_id", spanContext.TraceID().String()))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,145 @@
+// Package metrics defines the Prometheus collectors exported on /metrics.
+package metrics
+
+import (
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "dex_aggregator"
+
+// Outcome label values.
+const (
+	outcomeOK    = "ok"
+	outcomeError = "error"
+)
+
+var (
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	rpcCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "calls_total",
+		Help:      "Ethereum RPC calls by contract method and outcome.",
+	}, []string{"method", "outcome"})
+
+	rpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "call_duration_seconds",
+		Help:      "Ethereum RPC call latency by contract method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	graphQLRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "graphql",
+		Name:      "requests_total",
+		Help:      "Subgraph HTTP attempts by DEX and outcome, retries included.",
+	}, []string{"dex", "outcome"})
+
+	graphQLDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "graphql",
+		Name:      "request_duration_seconds",
+		Help:      "Subgraph HTTP attempt latency by DEX.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"dex"})
+
+	graphQLShortCircuited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "graphql",
+		Name:      "short_circuited_total",
+		Help:      "Subgraph queries rejected by an open circuit breaker, by DEX.",
+	}, []string{"dex"})
+
+	quoteSpread = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "quote",
+		Name:      "best_spread_bps",
+		Help:      "How much more the best pool pays than the second best, in basis points of the best output.",
+		Buckets:   []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 5000},
+	})
+	quoteNetSpread = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "quote",
+		Name:      "best_net_spread_bps",
+		Help:      "How much more the best pool pays net of gas than the second best, in basis points of the best net output.",
+		Buckets:   []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 5000},
+	})
+)
+
+// ObserveRPC records one RPC call that started at start.
+func ObserveRPC(method string, start time.Time, err error) {
+	rpcDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	rpcCalls.WithLabelValues(method, outcome(err)).Inc()
+}
+
+// ObserveGraphQL records one subgraph HTTP attempt that started at start.
+func ObserveGraphQL(dex string, start time.Time, err error) {
+	graphQLDuration.WithLabelValues(dex).Observe(time.Since(start).Seconds())
+	graphQLRequests.WithLabelValues(dex, outcome(err)).Inc()
+}
+
+func GraphQLShortCircuited(dex string) {
+	graphQLShortCircuited.WithLabelValues(dex).Inc()
+}
+
+// ObserveQuoteSpread records the spread between the best and second best
+// gross output amounts of a quote.
+func ObserveQuoteSpread(spreadBps float64) {
+	quoteSpread.Observe(spreadBps)
+}
+
+// ObserveQuoteNetSpread records the spread between the best and second best
+// output amounts net of gas, for quotes whose gas was priced.
+func ObserveQuoteNetSpread(spreadBps float64) {
+	quoteNetSpread.Observe(spreadBps)
+}
+
+// RegisterCacheStats exports the counters of a cache, read on every scrape.
+func RegisterCacheStats(provider domain.CacheStatsProvider) {
+	counter := func(name, help string, value func(domain.CacheStats) uint64) {
+		promauto.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      name,
+			Help:      help,
+		}, func() float64 {
+			return float64(value(provider.Stats()))
+		})
+	}
+	gauge := func(name, help string, value func(domain.CacheStats) float64) {
+		promauto.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      name,
+			Help:      help,
+		}, func() float64 {
+			return value(provider.Stats())
+		})
+	}
+
+	counter("hits_total", "Subgraph cache hits.", func(s domain.CacheStats) uint64 { return s.Hits })
+	counter("misses_total", "Subgraph cache misses.", func(s domain.CacheStats) uint64 { return s.Misses })
+	counter("stale_hits_total", "Subgraph cache hits served stale while refreshing.", func(s domain.CacheStats) uint64 { return s.StaleHits })
+	gauge("entries", "Entries currently held by the subgraph cache.", func(s domain.CacheStats) float64 { return float64(s.Entries) })
+	gauge("hit_ratio", "Share of subgraph cache lookups served from the cache.", func(s domain.CacheStats) float64 { return s.HitRatio })
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return outcomeError
+	}
+	return outcomeOK
+}
@@ -0,0 +1,120 @@
+package thegraph
+
+import (
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker trips after a run of consecutive failures against one
+// endpoint and rejects calls until the cooldown elapses. After the cooldown a
+// single probe request is let through; its outcome closes or re-opens the
+// breaker.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	threshold int
+	cooldown  time.Duration
+	probing   bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+func (b *circuitBreaker) allow() error {
+	if b.threshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return domain.ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		return nil
+	case breakerHalfOpen:
+		if b.probing {
+			return domain.ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = breakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *circuitBreaker) recordFailure() {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if b.state == breakerHalfOpen {
+		b.trip()
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.trip()
+	}
+}
+
+// release gives up a half-open probe slot without judging the endpoint, e.g.
+// when the caller's context was cancelled mid-request.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *circuitBreaker) trip() {
+	b.state = breakerOpen
+	b.openedAt = time.Now()
+	b.failures = 0
+}
+
+func (s *TheGraphService) breakerFor(endpoint string) *circuitBreaker {
+	s.breakersMu.Lock()
+	defer s.breakersMu.Unlock()
+
+	b, ok := s.breakers[endpoint]
+	if !ok {
+		b = newCircuitBreaker(s.options.BreakerThreshold, s.options.BreakerCooldown)
+		s.breakers[endpoint] = b
+	}
+	return b
+}
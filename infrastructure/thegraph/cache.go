@@ -0,0 +1,202 @@
+package thegraph
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"golang.org/x/sync/singleflight"
+)
+
+// CachedGraphService sits in front of a graph source and memoises query
+// results. Concurrent identical queries share one upstream call, and entries
+// past their TTL are still served for StaleTTL while a background refresh
+// runs.
+type CachedGraphService struct {
+	next    domain.TheGraphServiceInterface
+	options CacheOptions
+
+	mu      sync.RWMutex
+	entries map[string]*cacheEntry
+	group   singleflight.Group
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	staleHits atomic.Uint64
+}
+
+type CacheOptions struct {
+	TTL      time.Duration
+	StaleTTL time.Duration
+	// MaxEntries caps the number of cached queries. Zero means unbounded.
+	MaxEntries int
+	// RefreshTimeout bounds background revalidation, which runs detached
+	// from the request that triggered it.
+	RefreshTimeout time.Duration
+}
+
+type cacheEntry struct {
+	value     interface{}
+	fetchedAt time.Time
+}
+
+func NewCachedGraphService(next domain.TheGraphServiceInterface, options CacheOptions) *CachedGraphService {
+	return &CachedGraphService{
+		next:    next,
+		options: options,
+		entries: make(map[string]*cacheEntry),
+	}
+}
+
+func (c *CachedGraphService) GetPoolData(ctx context.Context, poolAddress string) (*domain.PoolData, error) {
+	key := cacheKey("GetPoolData", strings.ToLower(poolAddress))
+
+	value, err := c.get(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return c.next.GetPoolData(ctx, poolAddress)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*domain.PoolData), nil
+}
+
+func (c *CachedGraphService) GetPoolsByTokenPair(ctx context.Context, token0, token1 string) ([]*domain.PoolData, error) {
+	// The query matches either token order, so both orders share an entry.
+	tokens := []string{strings.ToLower(token0), strings.ToLower(token1)}
+	sort.Strings(tokens)
+	key := cacheKey("GetPoolsByTokenPair", tokens...)
+
+	value, err := c.get(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return c.next.GetPoolsByTokenPair(ctx, token0, token1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*domain.PoolData), nil
+}
+
+// GetAllPools is a periodic bulk crawl, so it bypasses the cache.
+func (c *CachedGraphService) GetAllPools(ctx context.Context) ([]*domain.PoolData, error) {
+	return c.next.GetAllPools(ctx)
+}
+
+func (c *CachedGraphService) Stats() domain.CacheStats {
+	c.mu.RLock()
+	entries := len(c.entries)
+	c.mu.RUnlock()
+
+	stats := domain.CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		StaleHits: c.staleHits.Load(),
+		Entries:   entries,
+	}
+
+	if total := stats.Hits + stats.StaleHits + stats.Misses; total > 0 {
+		stats.HitRatio = float64(stats.Hits+stats.StaleHits) / float64(total)
+	}
+
+	return stats
+}
+
+func (c *CachedGraphService) get(ctx context.Context, key string, fetch func(context.Context) (interface{}, error)) (interface{}, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if ok {
+		age := time.Since(entry.fetchedAt)
+		if age < c.options.TTL {
+			c.hits.Add(1)
+			return entry.value, nil
+		}
+		if age < c.options.TTL+c.options.StaleTTL {
+			c.staleHits.Add(1)
+			c.revalidate(ctx, key, fetch)
+			return entry.value, nil
+		}
+	}
+
+	c.misses.Add(1)
+
+	// The shared load must not die with whichever caller happened to start
+	// it, so it runs on a detached context; each caller still honours its
+	// own deadline below.
+	result := c.group.DoChan(key, func() (interface{}, error) {
+		loadCtx, cancel := c.detach(ctx)
+		defer cancel()
+		return c.load(loadCtx, key, fetch)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-result:
+		return res.Val, res.Err
+	}
+}
+
+func (c *CachedGraphService) revalidate(ctx context.Context, key string, fetch func(context.Context) (interface{}, error)) {
+	// DoChan dedupes refreshes triggered by concurrent stale hits; nobody
+	// waits on the result.
+	c.group.DoChan(key, func() (interface{}, error) {
+		loadCtx, cancel := c.detach(ctx)
+		defer cancel()
+		return c.load(loadCtx, key, fetch)
+	})
+}
+
+func (c *CachedGraphService) detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = context.WithoutCancel(ctx)
+	if c.options.RefreshTimeout > 0 {
+		return context.WithTimeout(ctx, c.options.RefreshTimeout)
+	}
+	return ctx, func() {}
+}
+
+func (c *CachedGraphService) load(ctx context.Context, key string, fetch func(context.Context) (interface{}, error)) (interface{}, error) {
+	value, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = &cacheEntry{value: value, fetchedAt: time.Now()}
+	if c.options.MaxEntries > 0 && len(c.entries) > c.options.MaxEntries {
+		c.evictLocked()
+	}
+	c.mu.Unlock()
+
+	return value, nil
+}
+
+// evictLocked drops entries that are past their stale window and, if the
+// cache is still over capacity, the oldest remaining ones.
+func (c *CachedGraphService) evictLocked() {
+	maxAge := c.options.TTL + c.options.StaleTTL
+	for key, entry := range c.entries {
+		if time.Since(entry.fetchedAt) >= maxAge {
+			delete(c.entries, key)
+		}
+	}
+
+	for len(c.entries) > c.options.MaxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for key, entry := range c.entries {
+			if oldestKey == "" || entry.fetchedAt.Before(oldest) {
+				oldestKey = key
+				oldest = entry.fetchedAt
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+}
+
+func cacheKey(query string, variables ...string) string {
+	return query + "|" + strings.Join(variables, "|")
+}
@@ -0,0 +1,175 @@
+package thegraph
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+// messariSchema speaks the Messari standardized DEX AMM schema
+// (liquidityPools, totalValueLockedUSD, inputTokens), which many forks
+// deploy instead of the Uniswap V2 one.
+type messariSchema struct{}
+
+type LiquidityPoolResponse struct {
+	LiquidityPool *LiquidityPoolData `json:"liquidityPool"`
+}
+
+type LiquidityPoolsResponse struct {
+	LiquidityPools []*LiquidityPoolData `json:"liquidityPools"`
+}
+
+type LiquidityPoolData struct {
+	ID                  string              `json:"id"`
+	InputTokens         []MessariToken      `json:"inputTokens"`
+	InputTokenBalances  []string            `json:"inputTokenBalances"`
+	OutputTokenSupply   string              `json:"outputTokenSupply"`
+	TotalValueLockedUSD string              `json:"totalValueLockedUSD"`
+	CumulativeVolumeUSD string              `json:"cumulativeVolumeUSD"`
+	DailySnapshots      []PoolDailySnapshot `json:"dailySnapshots"`
+}
+
+type MessariToken struct {
+	ID       string `json:"id"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+type PoolDailySnapshot struct {
+	DailyVolumeUSD       string `json:"dailyVolumeUSD"`
+	DailyTotalRevenueUSD string `json:"dailyTotalRevenueUSD"`
+}
+
+const messariPoolFields = `
+			id
+			inputTokens { id symbol decimals }
+			inputTokenBalances
+			outputTokenSupply
+			totalValueLockedUSD
+			cumulativeVolumeUSD
+			dailySnapshots(first: 1, orderBy: timestamp, orderDirection: desc) {
+				dailyVolumeUSD
+				dailyTotalRevenueUSD
+			}`
+
+func (messariSchema) Name() string {
+	return SchemaMessari
+}
+
+func (messariSchema) PoolQuery() string {
+	return `
+	query GetLiquidityPool($id: ID!) {
+		liquidityPool(id: $id) {` + messariPoolFields + `
+		}
+	}`
+}
+
+func (s messariSchema) DecodePool(data json.RawMessage) (*domain.PoolData, error) {
+	var resp LiquidityPoolResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.LiquidityPool == nil || len(resp.LiquidityPool.InputTokens) != 2 {
+		return nil, nil
+	}
+
+	return s.convertLiquidityPoolToPoolData(resp.LiquidityPool), nil
+}
+
+func (messariSchema) PoolsByTokenPairQuery() string {
+	return `
+	query GetLiquidityPools($token0: String!, $token1: String!) {
+		liquidityPools(
+			where: { inputTokens_contains: [$token0, $token1] },
+			orderBy: totalValueLockedUSD,
+			orderDirection: desc,
+			first: 10
+		) {` + messariPoolFields + `
+		}
+	}`
+}
+
+func (messariSchema) AllPoolsQuery() string {
+	return `
+	query GetAllLiquidityPools($first: Int!, $lastID: String!, $minTVL: BigDecimal!) {
+		liquidityPools(
+			where: { id_gt: $lastID, totalValueLockedUSD_gt: $minTVL },
+			orderBy: id,
+			orderDirection: asc,
+			first: $first
+		) {` + messariPoolFields + `
+		}
+	}`
+}
+
+func (s messariSchema) DecodePools(data json.RawMessage) ([]*domain.PoolData, error) {
+	var resp LiquidityPoolsResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	pools := make([]*domain.PoolData, 0, len(resp.LiquidityPools))
+	for _, p := range resp.LiquidityPools {
+		// Multi-asset pools cannot be expressed as a token0/token1 pair.
+		if len(p.InputTokens) != 2 {
+			continue
+		}
+		pools = append(pools, s.convertLiquidityPoolToPoolData(p))
+	}
+
+	return pools, nil
+}
+
+func (messariSchema) convertLiquidityPoolToPoolData(pool *LiquidityPoolData) *domain.PoolData {
+	tvlUSD, _ := strconv.ParseFloat(pool.TotalValueLockedUSD, 64)
+	volumeUSD, _ := strconv.ParseFloat(pool.CumulativeVolumeUSD, 64)
+
+	volume24hUSD, fees24hUSD := 0.0, 0.0
+	if len(pool.DailySnapshots) > 0 {
+		volume24hUSD, _ = strconv.ParseFloat(pool.DailySnapshots[0].DailyVolumeUSD, 64)
+		fees24hUSD, _ = strconv.ParseFloat(pool.DailySnapshots[0].DailyTotalRevenueUSD, 64)
+	}
+
+	// Messari reports balances in raw token units while the Uniswap schema
+	// uses whole units; normalise to the latter.
+	reserves := make([]string, 2)
+	for i := range reserves {
+		if i < len(pool.InputTokenBalances) {
+			reserves[i] = formatBalance(pool.InputTokenBalances[i], pool.InputTokens[i].Decimals)
+		}
+	}
+
+	return &domain.PoolData{
+		ID:           pool.ID,
+		Token0:       pool.InputTokens[0].ID,
+		Token1:       pool.InputTokens[1].ID,
+		Reserve0:     reserves[0],
+		Reserve1:     reserves[1],
+		TotalSupply:  formatBalance(pool.OutputTokenSupply, 18),
+		ReserveUSD:   tvlUSD,
+		VolumeUSD:    volumeUSD,
+		Volume24hUSD: volume24hUSD,
+		Fees24hUSD:   fees24hUSD,
+		Token0Symbol: pool.InputTokens[0].Symbol,
+		Token1Symbol: pool.InputTokens[1].Symbol,
+	}
+}
+
+func formatBalance(raw string, decimals int) string {
+	amount, ok := new(big.Int).SetString(raw, 10)
+	if !ok {
+		return raw
+	}
+
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	formatted := new(big.Rat).SetFrac(amount, divisor).FloatString(decimals)
+	if strings.Contains(formatted, ".") {
+		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+	}
+
+	return formatted
+}
@@ -0,0 +1,64 @@
+package thegraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+// metaQuery reads graph-node's indexing status, which every subgraph
+// exposes regardless of its entity schema.
+const metaQuery = `{ _meta { block { number } } }`
+
+type metaData struct {
+	Meta *struct {
+		Block struct {
+			Number uint64 `json:"number"`
+		} `json:"block"`
+	} `json:"_meta"`
+}
+
+// IndexedBlocks reports, per endpoint, the latest block its subgraph has
+// indexed. Endpoints are queried concurrently.
+func (s *TheGraphService) IndexedBlocks(ctx context.Context) []domain.SubgraphHead {
+	heads := make([]domain.SubgraphHead, len(s.endpoints))
+
+	var wg sync.WaitGroup
+	for i, endpoint := range s.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			heads[i] = domain.SubgraphHead{DEX: endpoint.DEX}
+			blockNumber, err := s.indexedBlock(ctx, endpoint)
+			if err != nil {
+				heads[i].Err = err
+				return
+			}
+			heads[i].BlockNumber = blockNumber
+		}()
+	}
+	wg.Wait()
+
+	return heads
+}
+
+func (s *TheGraphService) indexedBlock(ctx context.Context, endpoint Endpoint) (uint64, error) {
+	data, err := s.executeQuery(ctx, endpoint, metaQuery, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var meta metaData
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return 0, fmt.Errorf("failed to decode _meta: %w", err)
+	}
+	if meta.Meta == nil {
+		return 0, fmt.Errorf("subgraph returned no _meta")
+	}
+
+	return meta.Meta.Block.Number, nil
+}
@@ -0,0 +1,110 @@
+package thegraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type timeoutKey struct{}
+
+// WithTimeout overrides the per-attempt request timeout for GraphQL calls
+// made with the returned context.
+func WithTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, timeout)
+}
+
+func (s *TheGraphService) attemptTimeout(ctx context.Context) time.Duration {
+	if timeout, ok := ctx.Value(timeoutKey{}).(time.Duration); ok && timeout > 0 {
+		return timeout
+	}
+	return s.options.RequestTimeout
+}
+
+// statusError is returned for non-200 responses so the retry loop can decide
+// whether the failure is transient.
+type statusError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("GraphQL request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.StatusCode == http.StatusTooManyRequests || se.StatusCode >= http.StatusInternalServerError
+	}
+
+	// Transport errors and per-attempt deadlines are worth another try as
+	// long as the caller's context is still alive.
+	return true
+}
+
+// backoff returns a full-jitter exponential delay for the given attempt,
+// honouring a server supplied Retry-After when it is longer. It reports
+// false when Retry-After exceeds MaxRetryBackoff, as waiting it out would
+// hold the request for longer than any retry is allowed to.
+func (s *TheGraphService) backoff(attempt int, err error) (time.Duration, bool) {
+	base := s.options.RetryBackoff
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	limit := s.options.MaxRetryBackoff
+	if limit <= 0 {
+		limit = base
+	}
+
+	ceiling := base << attempt
+	if ceiling > limit || ceiling <= 0 {
+		ceiling = limit
+	}
+
+	delay := time.Duration(rand.Int63n(int64(ceiling) + 1))
+
+	var se *statusError
+	if errors.As(err, &se) && se.RetryAfter > delay {
+		if se.RetryAfter > limit {
+			return 0, false
+		}
+		delay = se.RetryAfter
+	}
+
+	return delay, true
+}
+
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,50 @@
+package thegraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+// Schema adapts one subgraph entity model to domain.PoolData. Each endpoint
+// is paired with the schema its subgraph implements.
+type Schema interface {
+	Name() string
+	// PoolQuery looks a pool up by its lowercase address ($id).
+	PoolQuery() string
+	// DecodePool returns nil when the subgraph does not know the pool.
+	DecodePool(data json.RawMessage) (*domain.PoolData, error)
+	// PoolsByTokenPairQuery lists pools holding both $token0 and $token1.
+	PoolsByTokenPairQuery() string
+	// AllPoolsQuery pages through pools above $minTVL in id order, starting
+	// after $lastID and returning at most $first entries.
+	AllPoolsQuery() string
+	DecodePools(data json.RawMessage) ([]*domain.PoolData, error)
+}
+
+const (
+	SchemaUniswapV2 = "uniswap-v2"
+	SchemaMessari   = "messari"
+)
+
+// SchemaByName resolves a schema from its configuration name.
+func SchemaByName(name string) (Schema, error) {
+	switch strings.ToLower(name) {
+	case "", SchemaUniswapV2:
+		return uniswapV2Schema{}, nil
+	case SchemaMessari:
+		return messariSchema{}, nil
+	default:
+		return nil, fmt.Errorf("unknown subgraph schema: %s", name)
+	}
+}
+
+// Endpoint is a subgraph URL together with the schema it serves. DEX labels
+// the pools it returns.
+type Endpoint struct {
+	URL    string
+	DEX    string
+	Schema Schema
+}
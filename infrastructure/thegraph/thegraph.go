@@ -4,20 +4,53 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/metrics"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 type TheGraphService struct {
-	client       *http.Client
-	uniswapV2URL string
-	minTVL       float64
+	client     *http.Client
+	endpoints  []Endpoint
+	minTVL     atomic.Uint64
+	options    Options
+	breakers   map[string]*circuitBreaker
+	breakersMu sync.Mutex
+}
+
+// Options tunes how the service talks to subgraph endpoints.
+type Options struct {
+	// RequestTimeout bounds a single HTTP attempt.
+	RequestTimeout time.Duration
+	// MaxRetries is the number of additional attempts made after a
+	// retryable failure (429, 5xx or transport error).
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// MaxRetryBackoff caps each wait between attempts, RetryBackoff when
+	// not positive. A Retry-After beyond it ends the retries.
+	MaxRetryBackoff time.Duration
+	// BreakerThreshold is the number of consecutive failed calls after which
+	// an endpoint is short-circuited. Zero disables the breaker.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	// PageSize is the number of pools fetched per GetAllPools page.
+	PageSize int
 }
 
 type GraphQLRequest struct {
@@ -34,204 +67,319 @@ type GraphQLError struct {
 	Message string `json:"message"`
 }
 
-type PairResponse struct {
-	Pair *PairData `json:"pair"`
-}
-
-type PairsResponse struct {
-	Pairs []*PairData `json:"pairs"`
-}
+var errNoEndpoints = errors.New("no subgraph endpoints configured")
 
-type PairData struct {
-	ID           string `json:"id"`
-	Token0       Token  `json:"token0"`
-	Token1       Token  `json:"token1"`
-	Reserve0     string `json:"reserve0"`
-	Reserve1     string `json:"reserve1"`
-	TotalSupply  string `json:"totalSupply"`
-	ReserveUSD   string `json:"reserveUSD"`
-	VolumeUSD    string `json:"volumeUSD"`
-	Volume24hUSD string `json:"volumeUSD24h,omitempty"`
-	Fees24hUSD   string `json:"feesUSD24h,omitempty"`
+func NewTheGraphService(endpoints []Endpoint, minTVL float64, options Options) *TheGraphService {
+	s := &TheGraphService{
+		// Timeouts are applied per attempt through the request context.
+		client:    &http.Client{},
+		endpoints: endpoints,
+		options:   options,
+		breakers:  make(map[string]*circuitBreaker),
+	}
+	s.SetMinTVL(minTVL)
+	return s
 }
 
-type Token struct {
-	ID     string `json:"id"`
-	Symbol string `json:"symbol"`
+// SetMinTVL changes the USD liquidity below which pools are dropped from
+// pair lookups and crawls.
+func (s *TheGraphService) SetMinTVL(minTVL float64) {
+	s.minTVL.Store(math.Float64bits(minTVL))
 }
 
-func NewTheGraphService(uniswapV2URL string, minTVL float64) *TheGraphService {
-	return &TheGraphService{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		uniswapV2URL: uniswapV2URL,
-		minTVL:       minTVL,
-	}
+func (s *TheGraphService) loadMinTVL() float64 {
+	return math.Float64frombits(s.minTVL.Load())
 }
 
+// GetPoolData asks each endpoint in turn and returns the first match.
 func (s *TheGraphService) GetPoolData(ctx context.Context, poolAddress string) (*domain.PoolData, error) {
-	query := `
-	query GetPair($id: ID!) {
-		pair(id: $id) {
-			id
-			token0 { id symbol }
-			token1 { id symbol }
-			reserve0
-			reserve1
-			totalSupply
-			reserveUSD
-			volumeUSD
-		}
-	}`
+	if len(s.endpoints) == 0 {
+		return nil, fmt.Errorf("GetPoolData failed: %w", errNoEndpoints)
+	}
 
 	vars := map[string]interface{}{
 		"id": strings.ToLower(poolAddress),
 	}
 
-	var resp PairResponse
-	if err := s.executeQuery(ctx, query, vars, &resp); err != nil {
-		return nil, fmt.Errorf("GetPoolData failed: %w", err)
+	var errs []error
+	for _, endpoint := range s.endpoints {
+		data, err := s.executeQuery(ctx, endpoint, endpoint.Schema.PoolQuery(), vars)
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping subgraph endpoint", "reason", "query failed", "error", err, "dex", endpoint.DEX, "pool", poolAddress)
+			errs = append(errs, err)
+			continue
+		}
+
+		poolData, err := endpoint.Schema.DecodePool(data)
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping subgraph endpoint", "reason", "decode failed", "error", err, "dex", endpoint.DEX, "pool", poolAddress)
+			errs = append(errs, fmt.Errorf("failed to decode %s pool: %w", endpoint.Schema.Name(), err))
+			continue
+		}
+		if poolData != nil {
+			poolData.DEX = endpoint.DEX
+			return poolData, nil
+		}
 	}
 
-	if resp.Pair == nil {
-		return nil, fmt.Errorf("pool not found: %s", poolAddress)
+	if len(errs) == len(s.endpoints) {
+		return nil, fmt.Errorf("GetPoolData failed: %w", errors.Join(errs...))
 	}
 
-	return s.convertPairToPoolData(resp.Pair), nil
+	return nil, fmt.Errorf("pool not found: %s", poolAddress)
 }
 
+// GetPoolsByTokenPair merges the pools every endpoint knows for the pair,
+// deepest first. It only fails when no endpoint could be queried.
 func (s *TheGraphService) GetPoolsByTokenPair(ctx context.Context, token0, token1 string) ([]*domain.PoolData, error) {
-	token0Lower := strings.ToLower(token0)
-	token1Lower := strings.ToLower(token1)
-
-	query := `
-	query GetPairs($token0: String!, $token1: String!) {
-		pairs(
-			where: {
-				_or: [
-					{ token0: $token0, token1: $token1 },
-					{ token0: $token1, token1: $token0 }
-				]
-			},
-			orderBy: reserveUSD,
-			orderDirection: desc,
-			first: 10
-		) {
-			id
-			token0 {
-				id
-				symbol
-			}
-			token1 {
-				id
-				symbol
+	if len(s.endpoints) == 0 {
+		return nil, fmt.Errorf("GetPoolsByTokenPair failed: %w", errNoEndpoints)
+	}
+
+	vars := map[string]interface{}{
+		"token0": strings.ToLower(token0),
+		"token1": strings.ToLower(token1),
+	}
+
+	seen := make(map[string]bool)
+	var pools []*domain.PoolData
+	var errs []error
+	for _, endpoint := range s.endpoints {
+		data, err := s.executeQuery(ctx, endpoint, endpoint.Schema.PoolsByTokenPairQuery(), vars)
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping subgraph endpoint", "reason", "query failed", "error", err, "dex", endpoint.DEX)
+			errs = append(errs, err)
+			continue
+		}
+
+		decoded, err := endpoint.Schema.DecodePools(data)
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping subgraph endpoint", "reason", "decode failed", "error", err, "dex", endpoint.DEX)
+			errs = append(errs, fmt.Errorf("failed to decode %s pools: %w", endpoint.Schema.Name(), err))
+			continue
+		}
+
+		for _, poolData := range decoded {
+			id := strings.ToLower(poolData.ID)
+			if seen[id] || poolData.ReserveUSD < s.loadMinTVL() {
+				continue
 			}
-			reserve0
-			reserve1
-			totalSupply
-			reserveUSD
-			volumeUSD
+			seen[id] = true
+			poolData.DEX = endpoint.DEX
+			pools = append(pools, poolData)
 		}
 	}
-`
 
-	vars := map[string]interface{}{
-		"token0": token0Lower,
-		"token1": token1Lower,
+	if len(errs) == len(s.endpoints) {
+		return nil, fmt.Errorf("GetPoolsByTokenPair failed: %w", errors.Join(errs...))
 	}
 
-	var resp PairsResponse
-	if err := s.executeQuery(ctx, query, vars, &resp); err != nil {
-		return nil, fmt.Errorf("GetPoolsByTokenPair failed: %w", err)
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].ReserveUSD > pools[j].ReserveUSD
+	})
+
+	return pools, nil
+}
+
+// GetAllPools crawls every endpoint with id_gt cursor pagination, which
+// unlike skip-based paging has no upper bound on how deep it can go.
+func (s *TheGraphService) GetAllPools(ctx context.Context) ([]*domain.PoolData, error) {
+	if len(s.endpoints) == 0 {
+		return nil, fmt.Errorf("GetAllPools failed: %w", errNoEndpoints)
 	}
 
+	pageSize := s.options.PageSize
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+
+	seen := make(map[string]bool)
 	var pools []*domain.PoolData
-	for _, p := range resp.Pairs {
-		poolData := s.convertPairToPoolData(p)
-		if poolData.ReserveUSD >= s.minTVL {
+	var errs []error
+	for _, endpoint := range s.endpoints {
+		endpointPools, err := s.crawlEndpoint(ctx, endpoint, pageSize)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", endpoint.URL, err))
+			continue
+		}
+
+		for _, poolData := range endpointPools {
+			id := strings.ToLower(poolData.ID)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
 			pools = append(pools, poolData)
 		}
 	}
 
+	if len(errs) > 0 {
+		return pools, fmt.Errorf("GetAllPools failed: %w", errors.Join(errs...))
+	}
+
 	return pools, nil
 }
 
-func (s *TheGraphService) executeQuery(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error {
-	reqBody := GraphQLRequest{
-		Query:     query,
-		Variables: variables,
+func (s *TheGraphService) crawlEndpoint(ctx context.Context, endpoint Endpoint, pageSize int) ([]*domain.PoolData, error) {
+	var pools []*domain.PoolData
+	lastID := ""
+
+	for {
+		vars := map[string]interface{}{
+			"first":  pageSize,
+			"lastID": lastID,
+			"minTVL": strconv.FormatFloat(s.loadMinTVL(), 'f', -1, 64),
+		}
+
+		data, err := s.executeQuery(ctx, endpoint, endpoint.Schema.AllPoolsQuery(), vars)
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := endpoint.Schema.DecodePools(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s pools: %w", endpoint.Schema.Name(), err)
+		}
+		for _, poolData := range page {
+			poolData.DEX = endpoint.DEX
+			pools = append(pools, poolData)
+		}
+
+		// The cursor comes from the raw page because schemas may drop
+		// entities they cannot represent while decoding.
+		cursor, count, err := pageCursor(data)
+		if err != nil {
+			return nil, err
+		}
+		if count < pageSize || cursor == "" {
+			return pools, nil
+		}
+		lastID = cursor
 	}
+}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal GraphQL request: %w", err)
+// pageCursor returns the id of the last entity in a single-collection
+// GraphQL result along with the number of entities on the page.
+func pageCursor(data json.RawMessage) (string, int, error) {
+	var collections map[string][]struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &collections); err != nil {
+		return "", 0, fmt.Errorf("failed to read page cursor: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", s.uniswapV2URL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	for _, entities := range collections {
+		if len(entities) == 0 {
+			return "", 0, nil
+		}
+		return entities[len(entities)-1].ID, len(entities), nil
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	return "", 0, nil
+}
 
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to execute GraphQL request: %w", err)
+func (s *TheGraphService) executeQuery(ctx context.Context, endpoint Endpoint, query string, variables map[string]interface{}) (json.RawMessage, error) {
+	reqBody := GraphQLRequest{
+		Query:     query,
+		Variables: variables,
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to marshal GraphQL request: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("GraphQL request failed with status %d: %s", resp.StatusCode, string(body))
+	breaker := s.breakerFor(endpoint.URL)
+	if err := breaker.allow(); err != nil {
+		metrics.GraphQLShortCircuited(endpoint.DEX)
+		return nil, err
 	}
 
+	var body []byte
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		attemptCtx, span := tracing.Start(ctx, "graphql "+endpoint.DEX,
+			attribute.String("dex", endpoint.DEX),
+			attribute.String("graphql.schema", endpoint.Schema.Name()),
+			attribute.Int("attempt", attempt),
+		)
+		body, err = s.doRequest(attemptCtx, endpoint.URL, jsonData)
+		metrics.ObserveGraphQL(endpoint.DEX, start, err)
+		tracing.End(span, err)
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			// The caller gave up; that says nothing about the endpoint.
+			breaker.release()
+			return nil, err
+		}
+		if !isRetryable(ctx, err) {
+			// The endpoint answered, it just rejected this request.
+			breaker.recordSuccess()
+			return nil, err
+		}
+		if attempt >= s.options.MaxRetries {
+			breaker.recordFailure()
+			return nil, err
+		}
+		delay, ok := s.backoff(attempt, err)
+		if !ok {
+			breaker.recordFailure()
+			return nil, err
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			breaker.release()
+			return nil, err
+		}
+	}
+	breaker.recordSuccess()
+
 	var graphQLResp GraphQLResponse
 	if err := json.Unmarshal(body, &graphQLResp); err != nil {
-		return fmt.Errorf("failed to unmarshal GraphQL response: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal GraphQL response: %w", err)
 	}
 
 	if len(graphQLResp.Errors) > 0 {
-		return fmt.Errorf("GraphQL errors: %v", graphQLResp.Errors)
+		return nil, fmt.Errorf("GraphQL errors: %v", graphQLResp.Errors)
 	}
 
-	if err := json.Unmarshal(graphQLResp.Data, result); err != nil {
-		return fmt.Errorf("failed to unmarshal GraphQL data: %w", err)
+	return graphQLResp.Data, nil
+}
+
+func (s *TheGraphService) doRequest(ctx context.Context, endpoint string, payload []byte) ([]byte, error) {
+	if timeout := s.attemptTimeout(ctx); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
-	return nil
-}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
 
-func (s *TheGraphService) convertPairToPoolData(pair *PairData) *domain.PoolData {
-	reserveUSD, _ := strconv.ParseFloat(pair.ReserveUSD, 64)
-	volumeUSD, _ := strconv.ParseFloat(pair.VolumeUSD, 64)
+	req.Header.Set("Content-Type", "application/json")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
-	volume24hUSD := 0.0
-	if pair.Volume24hUSD != "" {
-		volume24hUSD, _ = strconv.ParseFloat(pair.Volume24hUSD, 64)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute GraphQL request: %w", err)
 	}
+	defer resp.Body.Close()
 
-	fees24hUSD := 0.0
-	if pair.Fees24hUSD != "" {
-		fees24hUSD, _ = strconv.ParseFloat(pair.Fees24hUSD, 64)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	return &domain.PoolData{
-		ID:           pair.ID,
-		Token0:       pair.Token0.ID,
-		Token1:       pair.Token1.ID,
-		Reserve0:     pair.Reserve0,
-		Reserve1:     pair.Reserve1,
-		TotalSupply:  pair.TotalSupply,
-		ReserveUSD:   reserveUSD,
-		VolumeUSD:    volumeUSD,
-		Volume24hUSD: volume24hUSD,
-		Fees24hUSD:   fees24hUSD,
-		Token0Symbol: pair.Token0.Symbol,
-		Token1Symbol: pair.Token1.Symbol,
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
+
+	return body, nil
 }
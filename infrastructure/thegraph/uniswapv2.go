@@ -0,0 +1,169 @@
+package thegraph
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+// uniswapV2Schema speaks the Uniswap V2 subgraph (pair, reserveUSD, token0).
+// Most V2 forks such as Sushiswap deploy the same schema.
+type uniswapV2Schema struct{}
+
+type PairResponse struct {
+	Pair *PairData `json:"pair"`
+}
+
+type PairsResponse struct {
+	Pairs []*PairData `json:"pairs"`
+}
+
+type PairData struct {
+	ID           string `json:"id"`
+	Token0       Token  `json:"token0"`
+	Token1       Token  `json:"token1"`
+	Reserve0     string `json:"reserve0"`
+	Reserve1     string `json:"reserve1"`
+	TotalSupply  string `json:"totalSupply"`
+	ReserveUSD   string `json:"reserveUSD"`
+	VolumeUSD    string `json:"volumeUSD"`
+	Volume24hUSD string `json:"volumeUSD24h,omitempty"`
+	Fees24hUSD   string `json:"feesUSD24h,omitempty"`
+}
+
+type Token struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+}
+
+func (uniswapV2Schema) Name() string {
+	return SchemaUniswapV2
+}
+
+func (uniswapV2Schema) PoolQuery() string {
+	return `
+	query GetPair($id: ID!) {
+		pair(id: $id) {
+			id
+			token0 { id symbol }
+			token1 { id symbol }
+			reserve0
+			reserve1
+			totalSupply
+			reserveUSD
+			volumeUSD
+		}
+	}`
+}
+
+func (s uniswapV2Schema) DecodePool(data json.RawMessage) (*domain.PoolData, error) {
+	var resp PairResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Pair == nil {
+		return nil, nil
+	}
+
+	return s.convertPairToPoolData(resp.Pair), nil
+}
+
+func (uniswapV2Schema) PoolsByTokenPairQuery() string {
+	return `
+	query GetPairs($token0: String!, $token1: String!) {
+		pairs(
+			where: {
+				_or: [
+					{ token0: $token0, token1: $token1 },
+					{ token0: $token1, token1: $token0 }
+				]
+			},
+			orderBy: reserveUSD,
+			orderDirection: desc,
+			first: 10
+		) {
+			id
+			token0 {
+				id
+				symbol
+			}
+			token1 {
+				id
+				symbol
+			}
+			reserve0
+			reserve1
+			totalSupply
+			reserveUSD
+			volumeUSD
+		}
+	}
+`
+}
+
+func (uniswapV2Schema) AllPoolsQuery() string {
+	return `
+	query GetAllPairs($first: Int!, $lastID: String!, $minTVL: BigDecimal!) {
+		pairs(
+			where: { id_gt: $lastID, reserveUSD_gt: $minTVL },
+			orderBy: id,
+			orderDirection: asc,
+			first: $first
+		) {
+			id
+			token0 { id symbol }
+			token1 { id symbol }
+			reserve0
+			reserve1
+			totalSupply
+			reserveUSD
+			volumeUSD
+		}
+	}`
+}
+
+func (s uniswapV2Schema) DecodePools(data json.RawMessage) ([]*domain.PoolData, error) {
+	var resp PairsResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+
+	pools := make([]*domain.PoolData, 0, len(resp.Pairs))
+	for _, p := range resp.Pairs {
+		pools = append(pools, s.convertPairToPoolData(p))
+	}
+
+	return pools, nil
+}
+
+func (uniswapV2Schema) convertPairToPoolData(pair *PairData) *domain.PoolData {
+	reserveUSD, _ := strconv.ParseFloat(pair.ReserveUSD, 64)
+	volumeUSD, _ := strconv.ParseFloat(pair.VolumeUSD, 64)
+
+	volume24hUSD := 0.0
+	if pair.Volume24hUSD != "" {
+		volume24hUSD, _ = strconv.ParseFloat(pair.Volume24hUSD, 64)
+	}
+
+	fees24hUSD := 0.0
+	if pair.Fees24hUSD != "" {
+		fees24hUSD, _ = strconv.ParseFloat(pair.Fees24hUSD, 64)
+	}
+
+	return &domain.PoolData{
+		ID:           pair.ID,
+		Token0:       pair.Token0.ID,
+		Token1:       pair.Token1.ID,
+		Reserve0:     pair.Reserve0,
+		Reserve1:     pair.Reserve1,
+		TotalSupply:  pair.TotalSupply,
+		ReserveUSD:   reserveUSD,
+		VolumeUSD:    volumeUSD,
+		Volume24hUSD: volume24hUSD,
+		Fees24hUSD:   fees24hUSD,
+		Token0Symbol: pair.Token0.Symbol,
+		Token1Symbol: pair.Token1.Symbol,
+	}
+}
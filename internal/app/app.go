@@ -2,41 +2,168 @@ package app
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"reflect"
 	"syscall"
 	"time"
 
+	"github.com/DiDinar5/mini-dex-aggregator/aggregator"
 	"github.com/DiDinar5/mini-dex-aggregator/config"
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
 	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/ethereum"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/logging"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/metrics"
 	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/thegraph"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/tracing"
+	"github.com/DiDinar5/mini-dex-aggregator/internal/grpcserver"
 	"github.com/DiDinar5/mini-dex-aggregator/internal/handler"
+	"github.com/DiDinar5/mini-dex-aggregator/internal/middlewares/apikey"
+	"github.com/DiDinar5/mini-dex-aggregator/internal/middlewares/observability"
 	"github.com/DiDinar5/mini-dex-aggregator/internal/middlewares/validator"
-	"github.com/DiDinar5/mini-dex-aggregator/internal/usecase"
+	"github.com/DiDinar5/mini-dex-aggregator/internal/stream"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
 )
 
-func Run(cfg config.Config) {
-	ethereumService, err := ethereum.NewEthereumService(cfg.Ethereum.RPCURL)
+// Run serves until SIGINT or SIGTERM. configPath is re-read on SIGHUP to
+// apply the settings that can change without a restart.
+func Run(cfg config.Config, configPath string) {
+	// Claim SIGHUP before the slow startup work: unhandled, it would kill
+	// the process. A reload requested meanwhile runs once startup is done.
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	if _, err := logging.Setup(os.Stderr, logging.Options{Level: cfg.Log.Level, Format: cfg.Log.Format}); err != nil {
+		fatal("Failed to initialize logging", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:     cfg.Tracing.Exporter,
+		ServiceName:  cfg.Tracing.ServiceName,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		OTLPInsecure: cfg.Tracing.OTLPInsecure,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+	})
 	if err != nil {
-		log.Fatalf("Failed to initialize Ethereum service: %v", err)
+		fatal("Failed to initialize tracing", err)
 	}
 
-	graphService := thegraph.NewTheGraphService(cfg.TheGraph.UniswapV2URL, cfg.TheGraph.MinTVL)
+	endpoints, err := graphEndpoints(cfg.TheGraph)
+	if err != nil {
+		fatal("Failed to configure subgraph endpoints", err)
+	}
 
-	usecaseInstance := usecase.NewUsecase(ethereumService, graphService, cfg.TheGraph.MinTVL)
+	aggregatorOptions := []aggregator.Option{
+		aggregator.WithRPCURL(cfg.Ethereum.RPCURL),
+		aggregator.WithRPCTimeout(cfg.Ethereum.Timeout),
+		aggregator.WithBlockPollInterval(cfg.Ethereum.BlockPollInterval),
+		aggregator.WithGasOracle(ethereum.GasOracleOptions{
+			BlockCount:         cfg.Ethereum.GasOracle.BlockCount,
+			SlowPercentile:     cfg.Ethereum.GasOracle.SlowPercentile,
+			StandardPercentile: cfg.Ethereum.GasOracle.StandardPercentile,
+			FastPercentile:     cfg.Ethereum.GasOracle.FastPercentile,
+			FetchTimeout:       cfg.Ethereum.Timeout,
+		}),
+		aggregator.WithTokens(cfg.Tokens),
+		aggregator.WithMinTVL(cfg.TheGraph.MinTVL),
+		aggregator.WithHealthLimits(cfg.Health.MaxHeadAge, cfg.Health.MaxSubgraphLag, cfg.Health.ProbeTimeout),
+	}
+	if len(endpoints) > 0 {
+		aggregatorOptions = append(aggregatorOptions, aggregator.WithSubgraphs(endpoints, thegraph.Options{
+			RequestTimeout:   cfg.TheGraph.RequestTimeout,
+			MaxRetries:       cfg.TheGraph.MaxRetries,
+			RetryBackoff:     cfg.TheGraph.RetryBackoff,
+			MaxRetryBackoff:  cfg.TheGraph.MaxRetryBackoff,
+			BreakerThreshold: cfg.TheGraph.BreakerThreshold,
+			BreakerCooldown:  cfg.TheGraph.BreakerCooldown,
+			PageSize:         cfg.TheGraph.CrawlPageSize,
+		}))
+		if cfg.TheGraph.CrawlInterval > 0 {
+			aggregatorOptions = append(aggregatorOptions, aggregator.WithPoolCrawl(cfg.TheGraph.CrawlInterval))
+		}
+		if cfg.TheGraph.CacheTTL > 0 {
+			aggregatorOptions = append(aggregatorOptions, aggregator.WithCache(thegraph.CacheOptions{
+				TTL:            cfg.TheGraph.CacheTTL,
+				StaleTTL:       cfg.TheGraph.CacheStaleTTL,
+				MaxEntries:     cfg.TheGraph.CacheMaxEntries,
+				RefreshTimeout: 30 * time.Second,
+			}))
+		}
+	}
 
-	handlerInstance := handler.NewHandler(usecaseInstance)
+	agg, err := aggregator.New(aggregatorOptions...)
+	if err != nil {
+		fatal("Failed to initialize aggregator", err)
+	}
+
+	cacheStats := agg.CacheStats()
+	if cacheStats != nil {
+		metrics.RegisterCacheStats(cacheStats)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go agg.Run(ctx)
+
+	go reloadOnHangup(ctx, hangup, configPath, cfg, func(next config.Config) {
+		agg.SetMinTVL(next.TheGraph.MinTVL)
+		agg.SetTokens(next.Tokens)
+		// Validated by config.Load, so SetLevel cannot fail here.
+		_ = logging.SetLevel(next.Log.Level)
+	})
+
+	usecaseInstance := agg.Usecase()
+	blocks := agg.Blocks()
+
+	quoteHub := stream.NewHub(usecaseInstance, blocks, stream.Options{
+		MaxSubscriptions:  cfg.Stream.MaxSubscriptions,
+		SendBuffer:        cfg.Stream.SendBuffer,
+		MaxDroppedUpdates: cfg.Stream.MaxDroppedUpdates,
+		WriteTimeout:      cfg.Stream.WriteTimeout,
+	})
+	go quoteHub.Run(ctx)
+
+	keyStore, err := apiKeyStore(cfg.Auth)
+	if err != nil {
+		fatal("Failed to load API keys", err)
+	}
+
+	var usage domain.APIKeyUsageProvider
+	if keyStore != nil {
+		usage = keyStore
+	} else {
+		slog.Warn("No API keys configured, authentication is disabled")
+	}
+
+	handlerInstance := handler.NewHandler(usecaseInstance, cacheStats, usage, agg, agg.GasOracle(), quoteHub, blocks, cfg.Stream.MaxSSEPerClient)
 
 	e := echo.New()
 	e.HideBanner = true
 
+	e.IPExtractor = ipExtractor(cfg.Server.TrustedProxies)
 	e.Validator = validator.NewValidator()
-	e.Use(middleware.Logger())
-	e.Use(middleware.Recover())
+	e.HTTPErrorHandler = handler.HTTPErrorHandler
+	e.Use(observability.Tracing())
+	e.Use(observability.RequestID())
+	e.Use(observability.AccessLog())
+	e.Use(observability.Metrics())
+	e.Use(observability.Recover())
+	if keyStore != nil {
+		e.Use(apikey.Middleware(keyStore, apikey.Options{
+			Public:        []string{"/openapi.json", "/docs", "/metrics", "/healthz", "/readyz"},
+			QueryKeyPaths: []string{"/stream/price", "/ws/quote"},
+		}))
+	}
+
+	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 
 	handlerInstance.SetupRoutes(e)
 
@@ -46,29 +173,205 @@ func Run(cfg config.Config) {
 	}
 
 	go func() {
-		log.Printf("Starting server on %s:%s", cfg.Server.Host, cfg.Server.Port)
+		slog.Info("Starting server", "addr", server.Addr)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
+			fatal("Failed to start server", err)
 		}
 	}()
 
-	gracefulShutdown(server)
+	var grpcServer *grpc.Server
+	var grpcService *grpcserver.Server
+	if cfg.GRPC.Port != "" {
+		grpcAddr := cfg.GRPC.Host + ":" + cfg.GRPC.Port
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			fatal("Failed to listen for gRPC", err, "addr", grpcAddr)
+		}
+
+		var options []grpc.ServerOption
+		if keyStore != nil {
+			options = grpcserver.AuthInterceptors(keyStore)
+		}
+		grpcServer = grpc.NewServer(options...)
+		grpcService = grpcserver.NewServer(usecaseInstance, blocks)
+		grpcService.Register(grpcServer)
+
+		go func() {
+			slog.Info("Starting gRPC server", "addr", grpcAddr)
+			if err := grpcServer.Serve(listener); err != nil {
+				fatal("Failed to start gRPC server", err)
+			}
+		}()
+	}
+
+	setDraining := func() {
+		agg.SetDraining()
+		if grpcService != nil {
+			grpcService.SetDraining()
+		}
+	}
+	gracefulShutdown(server, setDraining, cfg.Health.DrainDelay, quoteHub, handlerInstance.CloseStreams, func(ctx context.Context) {
+		if grpcServer != nil {
+			grpcService.Shutdown()
+			stopGRPC(ctx, grpcServer)
+		}
+	})
+
+	// Flush spans buffered for export, including those of drained requests.
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer flushCancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
 
 }
 
-func gracefulShutdown(server *http.Server) {
+// reloadOnHangup re-reads the configuration on every SIGHUP received on
+// hangup and hands the reloadable settings to apply. Invalid files are
+// rejected whole, and changes to other settings are reported as needing a
+// restart.
+func reloadOnHangup(ctx context.Context, hangup <-chan os.Signal, configPath string, current config.Config, apply func(config.Config)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+		}
+
+		next, err := config.Load(configPath)
+		if err != nil {
+			slog.Error("Configuration reload failed, keeping the current settings", "error", err)
+			continue
+		}
+
+		apply(*next)
+
+		// Compare the rest with the reloadable settings taken over.
+		current.TheGraph.MinTVL = next.TheGraph.MinTVL
+		current.Tokens = next.Tokens
+		current.Log.Level = next.Log.Level
+		if !reflect.DeepEqual(current, *next) {
+			slog.Warn("Configuration changes other than thegraph.min_tvl, tokens and log.level need a restart")
+		}
+
+		slog.Info("Configuration reloaded", "min_tvl", next.TheGraph.MinTVL, "tokens", len(next.Tokens), "log_level", next.Log.Level)
+	}
+}
+
+func graphEndpoints(cfg config.TheGraphConfig) ([]thegraph.Endpoint, error) {
+	var endpoints []thegraph.Endpoint
+	if cfg.UniswapV2URL != "" {
+		schema, _ := thegraph.SchemaByName(thegraph.SchemaUniswapV2)
+		endpoints = append(endpoints, thegraph.Endpoint{URL: cfg.UniswapV2URL, DEX: "UniswapV2", Schema: schema})
+	}
+
+	for _, endpoint := range cfg.Endpoints {
+		schema, err := thegraph.SchemaByName(endpoint.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s: %w", endpoint.URL, err)
+		}
+		endpoints = append(endpoints, thegraph.Endpoint{URL: endpoint.URL, DEX: endpoint.DEX, Schema: schema})
+	}
+
+	return endpoints, nil
+}
+
+// apiKeyStore builds the key store, or returns nil when no keys are
+// configured.
+func apiKeyStore(cfg config.AuthConfig) (*apikey.Store, error) {
+	keyConfigs, err := cfg.LoadKeys()
+	if err != nil {
+		return nil, err
+	}
+	if len(keyConfigs) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]apikey.Key, 0, len(keyConfigs))
+	for _, key := range keyConfigs {
+		keys = append(keys, apikey.Key{
+			Name:       key.Name,
+			Key:        key.Key,
+			RateLimit:  key.RateLimit,
+			Burst:      key.Burst,
+			DailyQuota: key.DailyQuota,
+		})
+	}
+
+	return apikey.NewStore(keys)
+}
+
+func gracefulShutdown(server *http.Server, setDraining func(), drainDelay time.Duration, quoteHub *stream.Hub, closeStreams func(), shutdownGRPC func(context.Context)) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	<-quit
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server", "drain_delay", drainDelay)
+
+	// Fail readiness and gRPC health while still serving, so traffic moves
+	// away before the listeners close. A second signal skips the wait.
+	setDraining()
+	select {
+	case <-time.After(drainDelay):
+	case <-quit:
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// Hijacked WebSocket connections are not tracked by server.Shutdown, so
+	// drain them explicitly first.
+	if err := quoteHub.Shutdown(ctx); err != nil {
+		slog.Warn("Quote streams did not drain", "error", err)
+	}
+	// SSE responses never finish on their own, and server.Shutdown would
+	// otherwise wait for them until the timeout.
+	closeStreams()
+	shutdownGRPC(ctx)
+
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		fatal("Server forced to shutdown", err)
+	}
+
+	slog.Info("Server exited")
+}
+
+// ipExtractor reads the client IP from X-Forwarded-For only behind the
+// trusted proxies; otherwise clients could name any address themselves.
+func ipExtractor(trustedProxies []string) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
 	}
 
-	log.Println("Server exited")
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, cidr := range trustedProxies {
+		// Validated with the configuration.
+		if _, network, err := net.ParseCIDR(cidr); err == nil {
+			options = append(options, echo.TrustIPRange(network))
+		}
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// fatal logs err and exits, standing in for log.Fatal which slog lacks.
+func fatal(msg string, err error, args ...any) {
+	slog.Error(msg, append([]any{"error", err}, args...)...)
+	os.Exit(1)
+}
+
+// stopGRPC waits for in-flight calls to finish and forcibly closes the
+// remaining ones, such as open WatchQuote streams, once ctx is done.
+func stopGRPC(ctx context.Context, grpcServer *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		slog.Warn("gRPC server did not drain", "error", ctx.Err())
+		grpcServer.Stop()
+	}
 }
@@ -0,0 +1,75 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/DiDinar5/mini-dex-aggregator/internal/middlewares/apikey"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// publicServices are reachable without an API key so load balancers and
+// tooling can probe the server.
+var publicServices = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+// AuthInterceptors enforce the same API keys and limits as the REST
+// middleware. Keys are read from the x-api-key or authorization metadata.
+func AuthInterceptors(store *apikey.Store) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := authorize(ctx, store, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := authorize(stream.Context(), store, info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, stream)
+		}),
+	}
+}
+
+func authorize(ctx context.Context, store *apikey.Store, method string) error {
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(method, prefix) {
+			return nil
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	token := apikey.Token(first(md.Get(strings.ToLower(apikey.HeaderName))), first(md.Get("authorization")))
+
+	_, retryAfter, err := store.Allow(token)
+	if err == nil {
+		return nil
+	}
+	if retryAfter <= 0 {
+		return statusError(err)
+	}
+
+	// Mirror the REST Retry-After header and attach the standard RetryInfo
+	// detail for clients that understand it.
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", apikey.RetryAfterSeconds(retryAfter)))
+	st := status.Convert(statusError(err))
+	if detailed, detailErr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); detailErr == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
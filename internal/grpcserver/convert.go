@@ -0,0 +1,136 @@
+package grpcserver
+
+import (
+	aggregatorv1 "github.com/DiDinar5/mini-dex-aggregator/api/aggregator/v1"
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+func quoteRequestFromProto(req *aggregatorv1.QuoteRequest) domain.QuoteRequest {
+	return domain.QuoteRequest{
+		From:   req.GetFrom(),
+		To:     req.GetTo(),
+		Amount: req.GetAmount(),
+	}
+}
+
+func estimateRequestFromProto(req *aggregatorv1.EstimateRequest) domain.EstimateRequest {
+	return domain.EstimateRequest{
+		Pool:      req.GetPool(),
+		Src:       req.GetSrc(),
+		Dst:       req.GetDst(),
+		SrcAmount: req.GetSrcAmount(),
+	}
+}
+
+func batchRequestFromProto(req *aggregatorv1.BatchQuoteRequest) domain.BatchRequest {
+	batch := domain.BatchRequest{
+		Quotes:    make([]domain.QuoteRequest, 0, len(req.GetQuotes())),
+		Estimates: make([]domain.EstimateRequest, 0, len(req.GetEstimates())),
+	}
+	for _, quote := range req.GetQuotes() {
+		batch.Quotes = append(batch.Quotes, quoteRequestFromProto(quote))
+	}
+	for _, estimate := range req.GetEstimates() {
+		batch.Estimates = append(batch.Estimates, estimateRequestFromProto(estimate))
+	}
+
+	return batch
+}
+
+func quoteResponseToProto(response *domain.QuoteResponse) *aggregatorv1.QuoteResponse {
+	allQuotes := make([]*aggregatorv1.DEXQuote, 0, len(response.AllQuotes))
+	for i := range response.AllQuotes {
+		allQuotes = append(allQuotes, dexQuoteToProto(&response.AllQuotes[i]))
+	}
+
+	out := &aggregatorv1.QuoteResponse{
+		FromToken:   response.FromToken,
+		ToToken:     response.ToToken,
+		FromAmount:  response.FromAmount,
+		ToAmount:    response.ToAmount,
+		BestQuote:   dexQuoteToProto(&response.BestQuote),
+		AllQuotes:   allQuotes,
+		NetToAmount: response.NetToAmount,
+	}
+	if gas := response.Gas; gas != nil {
+		out.Gas = &aggregatorv1.GasInfo{
+			BaseFee:     gas.BaseFee,
+			PriorityFee: gas.PriorityFee,
+			GasPrice:    gas.GasPrice,
+			BlockNumber: gas.BlockNumber,
+		}
+	}
+
+	return out
+}
+
+func dexQuoteToProto(quote *domain.DEXQuote) *aggregatorv1.DEXQuote {
+	out := &aggregatorv1.DEXQuote{
+		Dex:         quote.DEX,
+		Pool:        quote.Pool,
+		ToAmount:    quote.ToAmount,
+		Price:       quote.Price,
+		NetToAmount: quote.NetToAmount,
+		GasEstimate: quote.GasEstimate,
+		GasCost:     quote.GasCost,
+	}
+	if info := quote.PoolInfo; info != nil {
+		out.PoolInfo = &aggregatorv1.PoolInfo{
+			Tvl:          info.TVL,
+			TvlSource:    info.TVLSource,
+			Volume_24H:   info.Volume24h,
+			Fees_24H:     info.Fees24h,
+			Reserve0:     info.Reserve0,
+			Reserve1:     info.Reserve1,
+			Token0Symbol: info.Token0Symbol,
+			Token1Symbol: info.Token1Symbol,
+			IsActive:     info.IsActive,
+		}
+	}
+
+	return out
+}
+
+func estimateResponseToProto(response *domain.EstimateResponse) *aggregatorv1.EstimateResponse {
+	return &aggregatorv1.EstimateResponse{
+		DstAmount: response.DstAmount,
+		PoolType:  string(response.PoolType),
+	}
+}
+
+func batchResponseToProto(response *domain.BatchResponse) *aggregatorv1.BatchQuoteResponse {
+	out := &aggregatorv1.BatchQuoteResponse{
+		BlockNumber: response.BlockNumber,
+		Quotes:      make([]*aggregatorv1.QuoteResult, 0, len(response.Quotes)),
+		Estimates:   make([]*aggregatorv1.EstimateResult, 0, len(response.Estimates)),
+	}
+
+	for _, result := range response.Quotes {
+		item := &aggregatorv1.QuoteResult{}
+		if result.Error != nil {
+			item.Outcome = &aggregatorv1.QuoteResult_Error{Error: errorResponseToProto(result.Error)}
+		} else if result.Result != nil {
+			item.Outcome = &aggregatorv1.QuoteResult_Result{Result: quoteResponseToProto(result.Result)}
+		}
+		out.Quotes = append(out.Quotes, item)
+	}
+
+	for _, result := range response.Estimates {
+		item := &aggregatorv1.EstimateResult{}
+		if result.Error != nil {
+			item.Outcome = &aggregatorv1.EstimateResult_Error{Error: errorResponseToProto(result.Error)}
+		} else if result.Result != nil {
+			item.Outcome = &aggregatorv1.EstimateResult_Result{Result: estimateResponseToProto(result.Result)}
+		}
+		out.Estimates = append(out.Estimates, item)
+	}
+
+	return out
+}
+
+func errorResponseToProto(response *domain.ErrorResponse) *aggregatorv1.Error {
+	return &aggregatorv1.Error{
+		ErrorCode: string(response.ErrorCode),
+		Message:   response.Description,
+	}
+}
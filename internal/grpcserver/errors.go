@@ -0,0 +1,71 @@
+package grpcserver
+
+import (
+	"log/slog"
+
+	aggregatorv1 "github.com/DiDinar5/mini-dex-aggregator/api/aggregator/v1"
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const errorDomain = "mini-dex-aggregator"
+
+// grpcCode maps an error code onto the gRPC status returned for it, the
+// counterpart of domain.ErrorCode.HTTPStatus.
+func grpcCode(code domain.ErrorCode) codes.Code {
+	switch code {
+	case domain.CodeInvalidInput:
+		return codes.InvalidArgument
+	case domain.CodeUnknownToken, domain.CodePoolNotFound, domain.CodeNotFound:
+		return codes.NotFound
+	case domain.CodeNoLiquidity, domain.CodeUnsupportedPool:
+		return codes.FailedPrecondition
+	case domain.CodeUpstreamUnavailable:
+		return codes.Unavailable
+	case domain.CodeTimeout:
+		return codes.DeadlineExceeded
+	case domain.CodeUnauthorized:
+		return codes.Unauthenticated
+	case domain.CodeRateLimited:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+// statusError converts err into a gRPC status carrying the same message as
+// the REST ErrorResponse and the domain error code as ErrorInfo.Reason.
+func statusError(err error) error {
+	response := domain.NewErrorResponse(err)
+
+	code := grpcCode(response.ErrorCode)
+	switch code {
+	case codes.Internal:
+		slog.Error("gRPC request failed", "error", err)
+	case codes.Unavailable, codes.DeadlineExceeded:
+		slog.Warn("gRPC request failed", "error", err)
+	}
+
+	st := status.New(code, response.Description)
+	if detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: string(response.ErrorCode),
+		Domain: errorDomain,
+	}); detailErr == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
+
+func errorToProto(err error) *aggregatorv1.Error {
+	response := domain.NewErrorResponse(err)
+	return errorResponseToProto(&response)
+}
+
+// invalidInput classifies a rejected request. The cause only describes the
+// client's own input, so it is shown to the client.
+func invalidInput(err error) error {
+	return domain.NewError(domain.CodeInvalidInput, "invalid request: "+err.Error(), nil)
+}
@@ -0,0 +1,173 @@
+package grpcserver
+
+import (
+	"context"
+	"sync"
+
+	aggregatorv1 "github.com/DiDinar5/mini-dex-aggregator/api/aggregator/v1"
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/internal/middlewares/validator"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Server implements aggregatorv1.AggregatorServiceServer on top of the same
+// usecase as the REST handlers.
+type Server struct {
+	aggregatorv1.UnimplementedAggregatorServiceServer
+
+	usecase   domain.UsecaseInterface
+	blocks    domain.BlockSourceInterface
+	validator *validator.CustomValid
+	health    *health.Server
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+func NewServer(usecase domain.UsecaseInterface, blocks domain.BlockSourceInterface) *Server {
+	return &Server{
+		usecase:   usecase,
+		blocks:    blocks,
+		validator: validator.NewValidator(),
+		health:    health.NewServer(),
+		closing:   make(chan struct{}),
+	}
+}
+
+// Register mounts the aggregator, health and reflection services on s.
+func (s *Server) Register(grpcServer *grpc.Server) {
+	aggregatorv1.RegisterAggregatorServiceServer(grpcServer, s)
+	healthpb.RegisterHealthServer(grpcServer, s.health)
+	reflection.Register(grpcServer)
+
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	s.health.SetServingStatus(aggregatorv1.AggregatorService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+}
+
+// SetDraining reports NOT_SERVING to health checkers so load balancers stop
+// routing new calls while the server still serves them.
+func (s *Server) SetDraining() {
+	s.health.Shutdown()
+}
+
+// Shutdown reports NOT_SERVING and ends open WatchQuote streams, which would
+// otherwise hold up grpc.Server.GracefulStop indefinitely.
+func (s *Server) Shutdown() {
+	s.closeOnce.Do(func() {
+		s.health.Shutdown()
+		close(s.closing)
+	})
+}
+
+func (s *Server) Quote(ctx context.Context, req *aggregatorv1.QuoteRequest) (*aggregatorv1.QuoteResponse, error) {
+	quoteReq := quoteRequestFromProto(req)
+	if err := s.validator.Validate(&quoteReq); err != nil {
+		return nil, statusError(invalidInput(err))
+	}
+
+	response, err := s.usecase.Quote(ctx, quoteReq)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return quoteResponseToProto(&response), nil
+}
+
+func (s *Server) Estimate(ctx context.Context, req *aggregatorv1.EstimateRequest) (*aggregatorv1.EstimateResponse, error) {
+	estimateReq := estimateRequestFromProto(req)
+	if err := s.validator.Validate(&estimateReq); err != nil {
+		return nil, statusError(invalidInput(err))
+	}
+
+	response, err := s.usecase.Estimate(ctx, estimateReq)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return estimateResponseToProto(&response), nil
+}
+
+func (s *Server) BatchQuote(ctx context.Context, req *aggregatorv1.BatchQuoteRequest) (*aggregatorv1.BatchQuoteResponse, error) {
+	batchReq := batchRequestFromProto(req)
+	if err := s.validator.Validate(&batchReq); err != nil {
+		return nil, statusError(invalidInput(err))
+	}
+
+	response, err := s.usecase.BatchQuote(ctx, batchReq)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return batchResponseToProto(&response), nil
+}
+
+// WatchQuote sends the quote at the current head, then a new message each
+// time a block changes it. Per-block failures are sent in-band so a
+// transient upstream error does not end the stream.
+func (s *Server) WatchQuote(req *aggregatorv1.WatchQuoteRequest, stream aggregatorv1.AggregatorService_WatchQuoteServer) error {
+	quoteReq := quoteRequestFromProto(req.GetQuote())
+	if err := s.validator.Validate(&quoteReq); err != nil {
+		return statusError(invalidInput(err))
+	}
+
+	ctx := stream.Context()
+
+	blocks, unsubscribe := s.blocks.Subscribe()
+	defer unsubscribe()
+
+	var (
+		lastBlock uint64
+		last      *aggregatorv1.WatchQuoteResponse
+	)
+	emit := func(blockNumber uint64) error {
+		if blockNumber <= lastBlock {
+			return nil
+		}
+		lastBlock = blockNumber
+
+		update := &aggregatorv1.WatchQuoteResponse{BlockNumber: blockNumber}
+		response, err := s.usecase.Quote(domain.WithBlockNumber(ctx, blockNumber), quoteReq)
+		if err != nil {
+			update.Outcome = &aggregatorv1.WatchQuoteResponse_Error{Error: errorToProto(err)}
+		} else {
+			update.Outcome = &aggregatorv1.WatchQuoteResponse_Quote{Quote: quoteResponseToProto(&response)}
+		}
+
+		// Only the outcome is compared; a new block number alone is not
+		// a change worth pushing.
+		if last != nil && proto.Equal(outcomeOf(last), outcomeOf(update)) {
+			return nil
+		}
+		last = update
+
+		return stream.Send(update)
+	}
+
+	if latest := s.blocks.Latest(); latest > 0 {
+		if err := emit(latest); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.closing:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case blockNumber := <-blocks:
+			if err := emit(blockNumber); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func outcomeOf(update *aggregatorv1.WatchQuoteResponse) *aggregatorv1.WatchQuoteResponse {
+	return &aggregatorv1.WatchQuoteResponse{Outcome: update.GetOutcome()}
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) BatchQuoteHandler(c echo.Context) error {
+	var req domain.BatchRequest
+
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, invalidInput(err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return errorJSON(c, invalidInput(err))
+	}
+
+	response, err := h.usecase.BatchQuote(c.Request().Context(), req)
+	if err != nil {
+		return errorJSON(c, err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
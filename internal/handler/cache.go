@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) CacheStatsHandler(c echo.Context) error {
+	if h.cacheStats == nil {
+		return c.JSON(http.StatusOK, domain.CacheStats{})
+	}
+
+	return c.JSON(http.StatusOK, h.cacheStats.Stats())
+}
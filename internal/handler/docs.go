@@ -0,0 +1,26 @@
+package handler
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// openAPISpec describes every route registered in SetupRoutes. The spec test
+// keeps the two in sync.
+//
+//go:embed docs/openapi.json
+var openAPISpec []byte
+
+//go:embed docs/index.html
+var docsPage []byte
+
+func (h *Handler) OpenAPIHandler(c echo.Context) error {
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, openAPISpec)
+}
+
+// DocsHandler serves an interactive page that renders /openapi.json.
+func (h *Handler) DocsHandler(c echo.Context) error {
+	return c.HTMLBlob(http.StatusOK, docsPage)
+}
@@ -1,22 +1,66 @@
 package handler
 
 import (
-	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 
 	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/labstack/echo/v4"
 )
 
-func errrorJson(statusCode int, message string, writer http.ResponseWriter) {
-	writer.WriteHeader(statusCode)
-	resp, err := json.Marshal(&domain.CommonResponse{
-		Message: message,
-		Status:  false,
-	})
-	if err != nil {
-		log.Println(err)
+// errorJSON writes err as a domain.ErrorResponse with the HTTP status its
+// classification maps to.
+func errorJSON(c echo.Context, err error) error {
+	response := domain.NewErrorResponse(err)
+	switch {
+	case response.Code == http.StatusInternalServerError:
+		slog.ErrorContext(c.Request().Context(), "Request failed", "method", c.Request().Method, "route", c.Path(), "error", err)
+	case response.Code > http.StatusInternalServerError:
+		slog.WarnContext(c.Request().Context(), "Request failed", "method", c.Request().Method, "route", c.Path(), "error", err)
 	}
 
-	writer.Write(resp)
+	return c.JSON(response.Code, response)
+}
+
+// invalidInput classifies a rejected request. The cause only describes the
+// client's own input, so it is shown to the client.
+func invalidInput(err error) error {
+	return domain.NewError(domain.CodeInvalidInput, "invalid request: "+err.Error(), nil)
+}
+
+// HTTPErrorHandler renders errors raised by Echo itself, such as unknown
+// routes or unsupported methods, in the same shape as handler errors.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) {
+		if err := errorJSON(c, err); err != nil {
+			slog.ErrorContext(c.Request().Context(), "Failed to write error response", "error", err)
+		}
+		return
+	}
+
+	code := domain.CodeInternal
+	switch {
+	case httpErr.Code == http.StatusNotFound:
+		code = domain.CodeNotFound
+	case httpErr.Code < http.StatusInternalServerError:
+		code = domain.CodeInvalidInput
+	}
+
+	response := domain.ErrorResponse{
+		Error:       http.StatusText(httpErr.Code),
+		Code:        httpErr.Code,
+		ErrorCode:   code,
+		Description: fmt.Sprint(httpErr.Message),
+	}
+
+	if err := c.JSON(httpErr.Code, response); err != nil {
+		slog.ErrorContext(c.Request().Context(), "Failed to write error response", "error", err)
+	}
 }
@@ -16,22 +16,16 @@ func (h *Handler) EstimateHandler(c echo.Context) error {
 		String("dst", &req.Dst).
 		String("src_amount", &req.SrcAmount).
 		BindError(); err != nil {
-		errrorJson(http.StatusBadRequest, err.Error(), c.Response().Writer)
-		return nil
+		return errorJSON(c, invalidInput(err))
 	}
 
 	if err := c.Validate(&req); err != nil {
-		errrorJson(http.StatusBadRequest, err.Error(), c.Response().Writer)
-		return nil
+		return errorJSON(c, invalidInput(err))
 	}
 
 	response, err := h.usecase.Estimate(c.Request().Context(), req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
-			Error:       "Estimation failed",
-			Code:        http.StatusInternalServerError,
-			Description: err.Error(),
-		})
+		return errorJSON(c, err)
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/labstack/echo/v4"
+)
+
+// GasHandler suggests slow, standard and fast EIP-1559 fees at the chain
+// head.
+func (h *Handler) GasHandler(c echo.Context) error {
+	suggestion, err := h.gas.GasSuggestion(c.Request().Context())
+	if err != nil {
+		return errorJSON(c, domain.NewUpstreamError("failed to suggest gas fees", err))
+	}
+
+	return c.JSON(http.StatusOK, domain.GasResponse{
+		BlockNumber: suggestion.BlockNumber,
+		BlockCount:  suggestion.BlockCount,
+		BaseFee:     suggestion.BaseFee.String(),
+		Slow:        gasTierInfo(suggestion.Slow),
+		Standard:    gasTierInfo(suggestion.Standard),
+		Fast:        gasTierInfo(suggestion.Fast),
+	})
+}
+
+func gasTierInfo(tier domain.GasTier) domain.GasTierInfo {
+	return domain.GasTierInfo{
+		Percentile:           tier.Percentile,
+		MaxFeePerGas:         tier.MaxFeePerGas.String(),
+		MaxPriorityFeePerGas: tier.MaxPriorityFeePerGas.String(),
+	}
+}
@@ -1,21 +1,77 @@
 package handler
 
 import (
+	"net/http"
+	"sync"
+
 	"github.com/DiDinar5/mini-dex-aggregator/domain"
 	"github.com/labstack/echo/v4"
 )
 
 type Handler struct {
-	usecase domain.UsecaseInterface
+	usecase       domain.UsecaseInterface
+	cacheStats    domain.CacheStatsProvider
+	usage         domain.APIKeyUsageProvider
+	readiness     domain.ReadinessProvider
+	gas           domain.GasSuggestionProvider
+	quoteStream   http.Handler
+	blocks        domain.BlockSourceInterface
+	streamLimiter *streamLimiter
+	closing       chan struct{}
+	closeOnce     sync.Once
 }
 
-func NewHandler(usecase domain.UsecaseInterface) *Handler {
+func NewHandler(usecase domain.UsecaseInterface, cacheStats domain.CacheStatsProvider, usage domain.APIKeyUsageProvider, readiness domain.ReadinessProvider, gas domain.GasSuggestionProvider, quoteStream http.Handler, blocks domain.BlockSourceInterface, maxStreamsPerClient int) *Handler {
 	return &Handler{
-		usecase: usecase,
+		usecase:       usecase,
+		cacheStats:    cacheStats,
+		usage:         usage,
+		readiness:     readiness,
+		gas:           gas,
+		quoteStream:   quoteStream,
+		blocks:        blocks,
+		streamLimiter: newStreamLimiter(maxStreamsPerClient),
+		closing:       make(chan struct{}),
 	}
 }
 
+// CloseStreams ends every open Server-Sent Events response.
+func (h *Handler) CloseStreams() {
+	h.closeOnce.Do(func() {
+		close(h.closing)
+	})
+}
+
 func (h *Handler) SetupRoutes(e *echo.Echo) {
 	e.GET("/estimate", h.EstimateHandler)
 	e.GET("/quote", h.QuoteHandler)
+	e.POST("/quote/batch", h.BatchQuoteHandler)
+	e.GET("/price", h.PriceHandler)
+	e.GET("/pools", h.PoolsHandler)
+	e.GET("/pools/:address", h.PoolDetailHandler)
+	e.GET("/tokens", h.TokensHandler)
+	e.GET("/cache/stats", h.CacheStatsHandler)
+	e.GET("/openapi.json", h.OpenAPIHandler)
+	e.GET("/docs", h.DocsHandler)
+	e.GET("/healthz", h.HealthzHandler)
+
+	if h.readiness != nil {
+		e.GET("/readyz", h.ReadyzHandler)
+	}
+
+	if h.gas != nil {
+		e.GET("/gas", h.GasHandler)
+	}
+
+	if h.usage != nil {
+		e.GET("/usage", h.UsageHandler)
+	}
+
+	if h.quoteStream != nil {
+		e.GET("/ws/quote", echo.WrapHandler(h.quoteStream))
+	}
+
+	if h.blocks != nil {
+		e.GET("/stream/price", h.PriceStreamHandler)
+	}
 }
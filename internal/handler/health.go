@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/labstack/echo/v4"
+)
+
+// HealthzHandler reports that the process is up; it checks no dependency.
+func (h *Handler) HealthzHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, domain.HealthResponse{Status: domain.HealthOK})
+}
+
+// ReadyzHandler probes the dependencies and answers 503 while any required
+// one is failing or the server is shutting down.
+func (h *Handler) ReadyzHandler(c echo.Context) error {
+	readiness := h.readiness.Readiness(c.Request().Context())
+
+	status := http.StatusOK
+	if !readiness.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+
+	return c.JSON(status, readiness)
+}
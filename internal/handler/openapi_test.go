@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/labstack/echo/v4"
+)
+
+// specSchemas binds every component schema to the domain type it documents.
+var specSchemas = map[string]reflect.Type{
+	"QuoteRequest":        reflect.TypeOf(domain.QuoteRequest{}),
+	"QuoteResponse":       reflect.TypeOf(domain.QuoteResponse{}),
+	"DEXQuote":            reflect.TypeOf(domain.DEXQuote{}),
+	"GasInfo":             reflect.TypeOf(domain.GasInfo{}),
+	"PoolInfo":            reflect.TypeOf(domain.PoolInfo{}),
+	"EstimateRequest":     reflect.TypeOf(domain.EstimateRequest{}),
+	"EstimateResponse":    reflect.TypeOf(domain.EstimateResponse{}),
+	"BatchRequest":        reflect.TypeOf(domain.BatchRequest{}),
+	"BatchResponse":       reflect.TypeOf(domain.BatchResponse{}),
+	"BatchQuoteResult":    reflect.TypeOf(domain.BatchQuoteResult{}),
+	"BatchEstimateResult": reflect.TypeOf(domain.BatchEstimateResult{}),
+	"PoolsResponse":       reflect.TypeOf(domain.PoolsResponse{}),
+	"PoolSummary":         reflect.TypeOf(domain.PoolSummary{}),
+	"CacheStats":          reflect.TypeOf(domain.CacheStats{}),
+	"PriceTick":           reflect.TypeOf(domain.PriceTick{}),
+	"StreamRequest":       reflect.TypeOf(domain.StreamRequest{}),
+	"StreamMessage":       reflect.TypeOf(domain.StreamMessage{}),
+	"ErrorResponse":       reflect.TypeOf(domain.ErrorResponse{}),
+	"APIKeyUsage":         reflect.TypeOf(domain.APIKeyUsage{}),
+	"Token":               reflect.TypeOf(domain.Token{}),
+	"TokensResponse":      reflect.TypeOf(domain.TokensResponse{}),
+	"PoolDetail":          reflect.TypeOf(domain.PoolDetail{}),
+	"PriceResponse":       reflect.TypeOf(domain.PriceResponse{}),
+	"PoolPrice":           reflect.TypeOf(domain.PoolPrice{}),
+	"HealthResponse":      reflect.TypeOf(domain.HealthResponse{}),
+	"ReadinessResponse":   reflect.TypeOf(domain.ReadinessResponse{}),
+	"DependencyCheck":     reflect.TypeOf(domain.DependencyCheck{}),
+	"GasResponse":         reflect.TypeOf(domain.GasResponse{}),
+	"GasTierInfo":         reflect.TypeOf(domain.GasTierInfo{}),
+}
+
+// specQueryRequests binds operations to the request type their query
+// parameters are bound into.
+var specQueryRequests = map[string]reflect.Type{
+	"quote":       reflect.TypeOf(domain.QuoteRequest{}),
+	"estimate":    reflect.TypeOf(domain.EstimateRequest{}),
+	"pools":       reflect.TypeOf(domain.PoolsRequest{}),
+	"price":       reflect.TypeOf(domain.PriceRequest{}),
+	"priceStream": reflect.TypeOf(domain.PriceTickRequest{}),
+}
+
+type specDocument struct {
+	Paths      map[string]map[string]specOperation `json:"paths"`
+	Components struct {
+		Schemas map[string]specSchema `json:"schemas"`
+	} `json:"components"`
+}
+
+type specOperation struct {
+	OperationID string `json:"operationId"`
+	Parameters  []struct {
+		Name     string `json:"name"`
+		In       string `json:"in"`
+		Required bool   `json:"required"`
+	} `json:"parameters"`
+}
+
+type specSchema struct {
+	Ref        string                `json:"$ref"`
+	Type       string                `json:"type"`
+	Properties map[string]specSchema `json:"properties"`
+	Required   []string              `json:"required"`
+	Items      *specSchema           `json:"items"`
+}
+
+type stubUsage struct{}
+
+func (stubUsage) Usage(string) (domain.APIKeyUsage, bool) { return domain.APIKeyUsage{}, false }
+
+type stubReadiness struct{}
+
+func (stubReadiness) Readiness(context.Context) domain.ReadinessResponse {
+	return domain.ReadinessResponse{}
+}
+
+type stubGas struct{}
+
+func (stubGas) GasSuggestion(context.Context) (*domain.GasSuggestion, error) {
+	return nil, nil
+}
+
+type stubBlocks struct{}
+
+func (stubBlocks) Subscribe() (<-chan uint64, func()) { return nil, func() {} }
+func (stubBlocks) Latest() uint64                     { return 0 }
+
+func loadSpec(t *testing.T) specDocument {
+	t.Helper()
+
+	var spec specDocument
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+	return spec
+}
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	spec := loadSpec(t)
+
+	e := echo.New()
+	NewHandler(nil, nil, stubUsage{}, stubReadiness{}, stubGas{}, http.NotFoundHandler(), stubBlocks{}, 0).SetupRoutes(e)
+
+	routes := make(map[string]bool)
+	for _, route := range e.Routes() {
+		routes[route.Method+" "+openAPIPath(route.Path)] = true
+	}
+
+	documented := make(map[string]bool)
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	for route := range routes {
+		if !documented[route] {
+			t.Errorf("route %s is not documented in openapi.json", route)
+		}
+	}
+	for route := range documented {
+		if !routes[route] {
+			t.Errorf("openapi.json documents %s, which is not registered", route)
+		}
+	}
+}
+
+func TestOpenAPISchemasMatchDomainTypes(t *testing.T) {
+	spec := loadSpec(t)
+
+	for name, schema := range spec.Components.Schemas {
+		typ, ok := specSchemas[name]
+		if !ok {
+			t.Errorf("schema %s is not bound to a domain type", name)
+			continue
+		}
+		checkStruct(t, name, schema, typ)
+	}
+	for name := range specSchemas {
+		if _, ok := spec.Components.Schemas[name]; !ok {
+			t.Errorf("domain type for %s has no schema", name)
+		}
+	}
+}
+
+func TestOpenAPIQueryParametersMatchRequests(t *testing.T) {
+	spec := loadSpec(t)
+
+	seen := make(map[string]bool)
+	for path, operations := range spec.Paths {
+		for method, operation := range operations {
+			typ, ok := specQueryRequests[operation.OperationID]
+			if !ok {
+				continue
+			}
+			seen[operation.OperationID] = true
+
+			fields := jsonFields(typ)
+			params := make(map[string]bool)
+			for _, param := range operation.Parameters {
+				if param.In != "query" {
+					continue
+				}
+				params[param.Name] = true
+				field, ok := fields[param.Name]
+				if !ok {
+					t.Errorf("%s %s: query parameter %q is not a field of %s", method, path, param.Name, typ.Name())
+					continue
+				}
+				if param.Required != field.required {
+					t.Errorf("%s %s: query parameter %q required=%v, %s says %v", method, path, param.Name, param.Required, typ.Name(), field.required)
+				}
+			}
+			for name := range fields {
+				if !params[name] {
+					t.Errorf("%s %s: %s.%s is not a documented query parameter", method, path, typ.Name(), name)
+				}
+			}
+		}
+	}
+
+	for operationID := range specQueryRequests {
+		if !seen[operationID] {
+			t.Errorf("operation %s is not in openapi.json", operationID)
+		}
+	}
+}
+
+type jsonField struct {
+	typ      reflect.Type
+	required bool
+}
+
+// jsonFields lists the JSON properties of a struct. Request types mark
+// required fields with validate:"required"; for the others every field that
+// is not omitempty is always present.
+func jsonFields(typ reflect.Type) map[string]jsonField {
+	isRequest := false
+	for i := 0; i < typ.NumField(); i++ {
+		if _, ok := typ.Field(i).Tag.Lookup("validate"); ok {
+			isRequest = true
+		}
+	}
+
+	fields := make(map[string]jsonField)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		required := !strings.Contains(options, "omitempty")
+		if isRequest {
+			required = strings.Contains(field.Tag.Get("validate"), "required")
+		}
+		fields[name] = jsonField{typ: field.Type, required: required}
+	}
+	return fields
+}
+
+func checkStruct(t *testing.T, name string, schema specSchema, typ reflect.Type) {
+	t.Helper()
+
+	fields := jsonFields(typ)
+
+	var wantRequired []string
+	for fieldName, field := range fields {
+		prop, ok := schema.Properties[fieldName]
+		if !ok {
+			t.Errorf("%s: field %q is missing from the schema", name, fieldName)
+			continue
+		}
+		if field.required {
+			wantRequired = append(wantRequired, fieldName)
+		}
+		checkType(t, name+"."+fieldName, prop, field.typ)
+	}
+	for propName := range schema.Properties {
+		if _, ok := fields[propName]; !ok {
+			t.Errorf("%s: property %q does not exist on %s", name, propName, typ.Name())
+		}
+	}
+
+	gotRequired := append([]string(nil), schema.Required...)
+	sort.Strings(gotRequired)
+	sort.Strings(wantRequired)
+	if strings.Join(gotRequired, ",") != strings.Join(wantRequired, ",") {
+		t.Errorf("%s: required = %v, want %v", name, gotRequired, wantRequired)
+	}
+}
+
+func checkType(t *testing.T, name string, schema specSchema, typ reflect.Type) {
+	t.Helper()
+
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if schema.Ref != "" {
+		refName := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		if bound := specSchemas[refName]; bound != typ {
+			t.Errorf("%s: references %s, but the field is %s", name, refName, typ)
+		}
+		return
+	}
+
+	var ok bool
+	switch schema.Type {
+	case "string":
+		ok = typ.Kind() == reflect.String || typ == reflect.TypeOf(time.Time{})
+	case "integer":
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			ok = true
+		}
+	case "number":
+		ok = typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64
+	case "boolean":
+		ok = typ.Kind() == reflect.Bool
+	case "array":
+		ok = typ.Kind() == reflect.Slice
+		if ok && schema.Items != nil {
+			checkType(t, name+"[]", *schema.Items, typ.Elem())
+		}
+	}
+	if !ok {
+		t.Errorf("%s: schema type %q does not match %s", name, schema.Type, typ)
+	}
+}
+
+// openAPIPath rewrites Echo path parameters (:id) in OpenAPI form ({id}).
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
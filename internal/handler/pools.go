@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) PoolsHandler(c echo.Context) error {
+	var req domain.PoolsRequest
+
+	if err := echo.QueryParamsBinder(c).
+		String("token", &req.Token).
+		String("dex", &req.DEX).
+		BindError(); err != nil {
+		return errorJSON(c, invalidInput(err))
+	}
+
+	response, err := h.usecase.Pools(c.Request().Context(), req)
+	if err != nil {
+		return errorJSON(c, err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) PoolDetailHandler(c echo.Context) error {
+	req := domain.PoolDetailRequest{
+		Address: c.Param("address"),
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return errorJSON(c, invalidInput(err))
+	}
+
+	response, err := h.usecase.PoolDetail(c.Request().Context(), req)
+	if err != nil {
+		return errorJSON(c, err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
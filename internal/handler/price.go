@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) PriceHandler(c echo.Context) error {
+	var req domain.PriceRequest
+
+	if err := echo.QueryParamsBinder(c).
+		String("base", &req.Base).
+		String("quote", &req.Quote).
+		BindError(); err != nil {
+		return errorJSON(c, invalidInput(err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return errorJSON(c, invalidInput(err))
+	}
+
+	response, err := h.usecase.Price(c.Request().Context(), req)
+	if err != nil {
+		return errorJSON(c, err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
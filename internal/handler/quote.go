@@ -15,22 +15,16 @@ func (h *Handler) QuoteHandler(c echo.Context) error {
 		String("to", &req.To).
 		String("amount", &req.Amount).
 		BindError(); err != nil {
-		errrorJson(http.StatusBadRequest, err.Error(), c.Response().Writer)
-		return nil
+		return errorJSON(c, invalidInput(err))
 	}
 
 	if err := c.Validate(&req); err != nil {
-		errrorJson(http.StatusBadRequest, err.Error(), c.Response().Writer)
-		return nil
+		return errorJSON(c, invalidInput(err))
 	}
 
 	response, err := h.usecase.Quote(c.Request().Context(), req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, domain.ErrorResponse{
-			Error:       "Quote failed",
-			Code:        http.StatusInternalServerError,
-			Description: err.Error(),
-		})
+		return errorJSON(c, err)
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/internal/middlewares/apikey"
+	"github.com/labstack/echo/v4"
+)
+
+const sseHeartbeatInterval = 15 * time.Second
+
+// streamLimiter caps concurrent streams per client.
+type streamLimiter struct {
+	mu     sync.Mutex
+	max    int
+	active map[string]int
+}
+
+func newStreamLimiter(max int) *streamLimiter {
+	return &streamLimiter{
+		max:    max,
+		active: make(map[string]int),
+	}
+}
+
+func (l *streamLimiter) acquire(client string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.max > 0 && l.active[client] >= l.max {
+		return false
+	}
+	l.active[client]++
+	return true
+}
+
+func (l *streamLimiter) release(client string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.active[client]--
+	if l.active[client] <= 0 {
+		delete(l.active, client)
+	}
+}
+
+// streamClient identifies who a stream is counted against: the API key when
+// the request was authenticated, else the client IP.
+func streamClient(c echo.Context) string {
+	if name, _ := c.Get(apikey.ContextKey).(string); name != "" {
+		return "key:" + name
+	}
+	return "ip:" + c.RealIP()
+}
+
+// PriceStreamHandler serves a Server-Sent Events ticker with one price
+// event per new block. Event IDs are block numbers, so a reconnecting
+// EventSource resumes via Last-Event-ID without replaying blocks it saw.
+func (h *Handler) PriceStreamHandler(c echo.Context) error {
+	var req domain.PriceTickRequest
+
+	if err := echo.QueryParamsBinder(c).
+		String("from", &req.From).
+		String("to", &req.To).
+		BindError(); err != nil {
+		return errorJSON(c, invalidInput(err))
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return errorJSON(c, invalidInput(err))
+	}
+
+	var lastBlock uint64
+	if lastEventID := c.Request().Header.Get("Last-Event-ID"); lastEventID != "" {
+		parsed, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			return errorJSON(c, invalidInput(fmt.Errorf("invalid Last-Event-ID: %w", err)))
+		}
+		lastBlock = parsed
+	}
+
+	client := streamClient(c)
+	if !h.streamLimiter.acquire(client) {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(sseHeartbeatInterval.Seconds())))
+		return c.JSON(http.StatusTooManyRequests, domain.ErrorResponse{
+			Error:       http.StatusText(http.StatusTooManyRequests),
+			Code:        http.StatusTooManyRequests,
+			ErrorCode:   domain.CodeRateLimited,
+			Description: "too many concurrent streams for this client",
+		})
+	}
+	defer h.streamLimiter.release(client)
+
+	blocks, unsubscribe := h.blocks.Subscribe()
+	defer unsubscribe()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	ctx := c.Request().Context()
+
+	emit := func(blockNumber uint64) error {
+		if blockNumber <= lastBlock {
+			return nil
+		}
+		lastBlock = blockNumber
+
+		tick, err := h.usecase.PriceTick(domain.WithBlockNumber(ctx, blockNumber), req)
+		if err != nil {
+			response := domain.NewErrorResponse(err)
+			if response.Code >= http.StatusInternalServerError {
+				slog.WarnContext(ctx, "Price tick failed", "block", blockNumber, "error", err)
+			}
+			return writeEvent(res, blockNumber, "error", response)
+		}
+		return writeEvent(res, blockNumber, "price", tick)
+	}
+
+	if latest := h.blocks.Latest(); latest > 0 {
+		if err := emit(latest); err != nil {
+			return nil
+		}
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-h.closing:
+			return nil
+		case blockNumber := <-blocks:
+			if err := emit(blockNumber); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+func writeEvent(res *echo.Response, id uint64, event string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", id, event, data); err != nil {
+		return err
+	}
+	res.Flush()
+
+	return nil
+}
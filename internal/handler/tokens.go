@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) TokensHandler(c echo.Context) error {
+	var req domain.TokensRequest
+
+	response, err := h.usecase.Tokens(c.Request().Context(), req)
+	if err != nil {
+		return errorJSON(c, err)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/internal/middlewares/apikey"
+	"github.com/labstack/echo/v4"
+)
+
+// UsageHandler reports the counters and limits of the calling API key.
+func (h *Handler) UsageHandler(c echo.Context) error {
+	name, _ := c.Get(apikey.ContextKey).(string)
+
+	usage, ok := h.usage.Usage(name)
+	if !ok {
+		return errorJSON(c, domain.NewError(domain.CodeUnauthorized, "missing API key", nil))
+	}
+
+	return c.JSON(http.StatusOK, usage)
+}
@@ -0,0 +1,186 @@
+// Package health probes the service's dependencies for the readiness
+// endpoint.
+package health
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+type Options struct {
+	// MaxHeadAge is how old the latest block may be before the node is
+	// considered stuck.
+	MaxHeadAge time.Duration
+	// MaxSubgraphLag is how many blocks a subgraph may trail the chain head.
+	MaxSubgraphLag uint64
+	// Timeout bounds each probe.
+	Timeout time.Duration
+}
+
+// Checker answers readiness probes. The RPC node and a fresh chain head are
+// required; subgraphs and the pool graph are optional, so a failing subgraph
+// or an uncrawled graph only degrades the result.
+type Checker struct {
+	chain     domain.ChainHeadSource
+	subgraphs domain.SubgraphHeadSource
+	poolGraph domain.PoolGraphInterface
+	options   Options
+	draining  atomic.Bool
+}
+
+// NewChecker builds a checker. subgraphs and poolGraph may be nil when no
+// subgraph is configured or the pool crawl is disabled.
+func NewChecker(chain domain.ChainHeadSource, subgraphs domain.SubgraphHeadSource, poolGraph domain.PoolGraphInterface, options Options) *Checker {
+	return &Checker{
+		chain:     chain,
+		subgraphs: subgraphs,
+		poolGraph: poolGraph,
+		options:   options,
+	}
+}
+
+// SetDraining makes every later probe report not-ready, so load balancers
+// stop routing new traffic while in-flight requests finish.
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+func (c *Checker) Readiness(ctx context.Context) domain.ReadinessResponse {
+	if c.draining.Load() {
+		return domain.ReadinessResponse{Status: domain.HealthDraining, Checks: []domain.DependencyCheck{}}
+	}
+
+	if c.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.options.Timeout)
+		defer cancel()
+	}
+
+	var head domain.BlockHeader
+	var rpc domain.DependencyCheck
+	var subgraphHeads []domain.SubgraphHead
+	var subgraphLatency time.Duration
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		head, rpc = c.checkRPC(ctx)
+	}()
+	if c.subgraphs != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			subgraphHeads = c.subgraphs.IndexedBlocks(ctx)
+			subgraphLatency = time.Since(start)
+		}()
+	}
+	wg.Wait()
+
+	checks := []domain.DependencyCheck{rpc}
+	for _, subgraphHead := range subgraphHeads {
+		checks = append(checks, c.checkSubgraph(ctx, subgraphHead, head, rpc.Status == domain.HealthOK, subgraphLatency))
+	}
+	if c.poolGraph != nil {
+		checks = append(checks, c.checkPoolGraph())
+	}
+
+	status := domain.HealthOK
+	for _, check := range checks {
+		switch check.Status {
+		case domain.HealthFailing:
+			status = domain.HealthFailing
+		case domain.HealthDegraded:
+			if status == domain.HealthOK {
+				status = domain.HealthDegraded
+			}
+		}
+	}
+
+	return domain.ReadinessResponse{Status: status, Checks: checks}
+}
+
+func (c *Checker) checkRPC(ctx context.Context) (domain.BlockHeader, domain.DependencyCheck) {
+	check := domain.DependencyCheck{Name: "rpc"}
+
+	start := time.Now()
+	head, err := c.chain.HeadBlock(ctx)
+	check.LatencyMs = time.Since(start).Milliseconds()
+	// The error can quote the RPC URL and its key, and readiness is served
+	// without authentication, so only the log gets it.
+	if err != nil {
+		slog.WarnContext(ctx, "Readiness probe failed", "dependency", check.Name, "error", err)
+		check.Status = domain.HealthFailing
+		check.Message = "rpc unreachable"
+		return head, check
+	}
+
+	age := time.Since(head.Time)
+	check.BlockNumber = head.Number
+	check.HeadAgeSeconds = age.Round(time.Second).Seconds()
+
+	if c.options.MaxHeadAge > 0 && age > c.options.MaxHeadAge {
+		check.Status = domain.HealthFailing
+		check.Message = fmt.Sprintf("chain head is %s old, limit %s", age.Round(time.Second), c.options.MaxHeadAge)
+		return head, check
+	}
+
+	check.Status = domain.HealthOK
+	return head, check
+}
+
+func (c *Checker) checkSubgraph(ctx context.Context, subgraphHead domain.SubgraphHead, head domain.BlockHeader, haveHead bool, latency time.Duration) domain.DependencyCheck {
+	check := domain.DependencyCheck{
+		Name:      "subgraph:" + subgraphHead.DEX,
+		LatencyMs: latency.Milliseconds(),
+	}
+
+	if subgraphHead.Err != nil {
+		slog.WarnContext(ctx, "Readiness probe failed", "dependency", check.Name, "error", subgraphHead.Err)
+		check.Status = domain.HealthDegraded
+		check.Message = "subgraph unreachable"
+		return check
+	}
+
+	check.Status = domain.HealthOK
+	check.BlockNumber = subgraphHead.BlockNumber
+	if !haveHead {
+		return check
+	}
+
+	var lag uint64
+	if head.Number > subgraphHead.BlockNumber {
+		lag = head.Number - subgraphHead.BlockNumber
+	}
+	check.LagBlocks = &lag
+
+	if c.options.MaxSubgraphLag > 0 && lag > c.options.MaxSubgraphLag {
+		check.Status = domain.HealthDegraded
+		check.Message = fmt.Sprintf("subgraph is %d blocks behind, limit %d", lag, c.options.MaxSubgraphLag)
+	}
+
+	return check
+}
+
+// checkPoolGraph is degraded until the first crawl has filled the pool
+// graph. Quotes still find pools through the DEX factories meanwhile, and a
+// subgraph that is down at boot would otherwise keep the service unready.
+func (c *Checker) checkPoolGraph() domain.DependencyCheck {
+	check := domain.DependencyCheck{Name: "pool_graph", Status: domain.HealthOK}
+
+	if c.poolGraph.UpdatedAt().IsZero() {
+		check.Status = domain.HealthDegraded
+		check.Message = "warming up: first pool crawl has not finished"
+		return check
+	}
+
+	check.Message = fmt.Sprintf("%d pools, crawled %s", c.poolGraph.Size(), c.poolGraph.UpdatedAt().UTC().Format(time.RFC3339))
+	return check
+}
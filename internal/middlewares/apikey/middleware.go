@@ -0,0 +1,94 @@
+package apikey
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderName is the request header carrying the API key. A bearer token in
+// the Authorization header is accepted as well.
+const HeaderName = "X-API-Key"
+
+// QueryParam is the query parameter carrying the API key on the paths in
+// Options.QueryKeyPaths.
+const QueryParam = "api_key"
+
+// ContextKey is the echo.Context key under which the authenticated key name
+// is stored.
+const ContextKey = "api_key"
+
+// Options selects the routes Middleware treats differently.
+type Options struct {
+	// Public routes bypass authentication.
+	Public []string
+	// QueryKeyPaths also take the key from the QueryParam query parameter.
+	// Browser EventSource and WebSocket clients cannot set headers, so
+	// streaming routes need it; elsewhere keys stay out of URLs, which end
+	// up in logs and browser history.
+	QueryKeyPaths []string
+}
+
+// Middleware rejects requests without a valid key and charges the rest
+// against the key's limits.
+func Middleware(store *Store, options Options) echo.MiddlewareFunc {
+	skip := pathSet(options.Public)
+	queryKey := pathSet(options.QueryKeyPaths)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skip[c.Path()] {
+				return next(c)
+			}
+
+			token := Token(c.Request().Header.Get(HeaderName), c.Request().Header.Get(echo.HeaderAuthorization))
+			if token == "" && queryKey[c.Path()] {
+				token = c.QueryParam(QueryParam)
+			}
+
+			name, retryAfter, err := store.Allow(token)
+			if err != nil {
+				if retryAfter > 0 {
+					c.Response().Header().Set("Retry-After", RetryAfterSeconds(retryAfter))
+				}
+				response := domain.NewErrorResponse(err)
+				return c.JSON(response.Code, response)
+			}
+
+			c.Set(ContextKey, name)
+			return next(c)
+		}
+	}
+}
+
+func pathSet(paths []string) map[string]bool {
+	set := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		set[path] = true
+	}
+	return set
+}
+
+// Token picks the API key from the dedicated header or, failing that, an
+// Authorization bearer token.
+func Token(apiKeyHeader, authorization string) string {
+	if apiKeyHeader != "" {
+		return apiKeyHeader
+	}
+
+	if scheme, token, ok := strings.Cut(authorization, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+
+	return ""
+}
+
+// RetryAfterSeconds formats d as a Retry-After value, rounding up so
+// clients never retry early.
+func RetryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,157 @@
+package apikey
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"golang.org/x/time/rate"
+)
+
+// Key is one client credential and its limits. A zero RateLimit or
+// DailyQuota leaves that dimension unlimited.
+type Key struct {
+	Name       string
+	Key        string
+	RateLimit  float64
+	Burst      int
+	DailyQuota int
+}
+
+// Store authenticates API keys and enforces their token-bucket rate limit
+// and daily quota. Quotas reset at midnight UTC.
+type Store struct {
+	mu     sync.Mutex
+	byKey  map[string]*keyState
+	byName map[string]*keyState
+	now    func() time.Time
+}
+
+type keyState struct {
+	key     Key
+	limiter *rate.Limiter
+
+	day           time.Time
+	requestsToday int
+	requestsTotal uint64
+	rateLimited   uint64
+	quotaExceeded uint64
+}
+
+func NewStore(keys []Key) (*Store, error) {
+	s := &Store{
+		byKey:  make(map[string]*keyState, len(keys)),
+		byName: make(map[string]*keyState, len(keys)),
+		now:    time.Now,
+	}
+
+	for _, key := range keys {
+		if key.Name == "" {
+			return nil, fmt.Errorf("api key without a name")
+		}
+		if key.Key == "" {
+			return nil, fmt.Errorf("api key %q has an empty key", key.Name)
+		}
+		if _, exists := s.byKey[key.Key]; exists {
+			return nil, fmt.Errorf("api key %q reuses another key's secret", key.Name)
+		}
+		if _, exists := s.byName[key.Name]; exists {
+			return nil, fmt.Errorf("api key name %q is used twice", key.Name)
+		}
+
+		limit := rate.Inf
+		if key.RateLimit > 0 {
+			limit = rate.Limit(key.RateLimit)
+		}
+		burst := key.Burst
+		if burst <= 0 {
+			burst = max(1, int(key.RateLimit))
+		}
+
+		state := &keyState{key: key, limiter: rate.NewLimiter(limit, burst)}
+		s.byKey[key.Key] = state
+		s.byName[key.Name] = state
+	}
+
+	return s, nil
+}
+
+// Len returns the number of configured keys.
+func (s *Store) Len() int {
+	return len(s.byKey)
+}
+
+// Allow authenticates token and charges one request against its limits. It
+// returns the key name on success. Rejections are domain errors; for rate
+// limit and quota rejections retryAfter says when to try again.
+func (s *Store) Allow(token string) (name string, retryAfter time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.byKey[token]
+	if !ok {
+		if token == "" {
+			return "", 0, domain.NewError(domain.CodeUnauthorized, "missing API key", nil)
+		}
+		return "", 0, domain.NewError(domain.CodeUnauthorized, "invalid API key", nil)
+	}
+
+	now := s.now()
+	state.rollover(now)
+
+	if quota := state.key.DailyQuota; quota > 0 && state.requestsToday >= quota {
+		state.quotaExceeded++
+		return state.key.Name, nextDay(now).Sub(now), domain.NewError(domain.CodeRateLimited, fmt.Sprintf("daily quota of %d requests exceeded", quota), nil)
+	}
+
+	reservation := state.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		state.rateLimited++
+		return state.key.Name, delay, domain.NewError(domain.CodeRateLimited, "rate limit exceeded", nil)
+	}
+
+	state.requestsToday++
+	state.requestsTotal++
+
+	return state.key.Name, 0, nil
+}
+
+func (s *Store) Usage(name string) (domain.APIKeyUsage, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.byName[name]
+	if !ok {
+		return domain.APIKeyUsage{}, false
+	}
+
+	now := s.now()
+	state.rollover(now)
+
+	return domain.APIKeyUsage{
+		Name:          state.key.Name,
+		RequestsToday: state.requestsToday,
+		DailyQuota:    state.key.DailyQuota,
+		RateLimit:     state.key.RateLimit,
+		Burst:         state.limiter.Burst(),
+		RequestsTotal: state.requestsTotal,
+		RateLimited:   state.rateLimited,
+		QuotaExceeded: state.quotaExceeded,
+		QuotaResetAt:  nextDay(now),
+	}, true
+}
+
+// rollover resets the daily counter once the UTC day has changed.
+func (k *keyState) rollover(now time.Time) {
+	day := now.UTC().Truncate(24 * time.Hour)
+	if !day.Equal(k.day) {
+		k.day = day
+		k.requestsToday = 0
+	}
+}
+
+func nextDay(now time.Time) time.Time {
+	return now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+}
@@ -0,0 +1,89 @@
+package observability
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/logging"
+	"github.com/DiDinar5/mini-dex-aggregator/internal/middlewares/apikey"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// RequestID assigns every request an ID, honouring an X-Request-ID sent by
+// the caller, echoes it in the response and attaches it to the request
+// context for logging.
+func RequestID() echo.MiddlewareFunc {
+	return middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, requestID string) {
+			req := c.Request()
+			c.SetRequest(req.WithContext(logging.WithRequestID(req.Context(), requestID)))
+		},
+	})
+}
+
+// AccessLog logs one record per request; server errors at error level and
+// client errors at warn.
+func AccessLog() echo.MiddlewareFunc {
+	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		LogMethod:    true,
+		LogURI:       true,
+		LogRoutePath: true,
+		LogStatus:    true,
+		LogLatency:   true,
+		LogRemoteIP:  true,
+		LogError:     true,
+		HandleError:  true,
+		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
+			level := slog.LevelInfo
+			switch {
+			case v.Status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case v.Status >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", v.Method),
+				slog.String("uri", redactURI(v.URI)),
+				slog.String("route", v.RoutePath),
+				slog.Int("status", v.Status),
+				slog.Duration("latency", v.Latency),
+				slog.String("remote_ip", v.RemoteIP),
+			}
+			if v.Error != nil {
+				attrs = append(attrs, slog.String("error", v.Error.Error()))
+			}
+
+			slog.LogAttrs(c.Request().Context(), level, "request", attrs...)
+			return nil
+		},
+	})
+}
+
+// redactURI masks an API key passed as a query parameter, so it does not
+// end up in the logs.
+func redactURI(uri string) string {
+	path, rawQuery, ok := strings.Cut(uri, "?")
+	if !ok {
+		return uri
+	}
+	query, _ := url.ParseQuery(rawQuery)
+	if !query.Has(apikey.QueryParam) {
+		return uri
+	}
+	query.Set(apikey.QueryParam, "REDACTED")
+	return path + "?" + query.Encode()
+}
+
+// Recover turns panics into 500 responses and logs them with their stack.
+func Recover() echo.MiddlewareFunc {
+	return middleware.RecoverWithConfig(middleware.RecoverConfig{
+		LogErrorFunc: func(c echo.Context, err error, stack []byte) error {
+			slog.ErrorContext(c.Request().Context(), "panic recovered", "error", err, "stack", string(stack))
+			return err
+		},
+	})
+}
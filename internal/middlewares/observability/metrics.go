@@ -0,0 +1,55 @@
+package observability
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/metrics"
+	"github.com/labstack/echo/v4"
+)
+
+// unmatchedRoute labels requests that matched no route, so scanners cannot
+// blow up the label cardinality with arbitrary paths.
+const unmatchedRoute = "unmatched"
+
+// Metrics records the latency of every request by route template and
+// status code.
+func Metrics() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			metrics.HTTPRequestDuration.
+				WithLabelValues(c.Request().Method, route(c), strconv.Itoa(status(c, err))).
+				Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
+
+// route is the matched route template, never the raw path.
+func route(c echo.Context) string {
+	if path := c.Path(); path != "" {
+		return path
+	}
+	return unmatchedRoute
+}
+
+// status is the code the response will have once err, if any, has been
+// rendered by the error handler.
+func status(c echo.Context, err error) int {
+	if err == nil || c.Response().Committed {
+		return c.Response().Status
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+
+	return domain.NewErrorResponse(err).Code
+}
@@ -0,0 +1,49 @@
+package observability
+
+import (
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/tracing"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// TraceIDHeader echoes the trace ID of every traced request so a slow or
+// failed call can be looked up in the tracing backend.
+const TraceIDHeader = "X-Trace-Id"
+
+// Tracing opens a server span per request, continuing a trace propagated
+// by the caller through the traceparent header.
+func Tracing() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			ctx, span := tracing.StartServer(ctx, req.Method+" "+route(c),
+				attribute.String("http.request.method", req.Method),
+				attribute.String("http.route", route(c)),
+			)
+			defer span.End()
+
+			if spanContext := span.SpanContext(); spanContext.HasTraceID() {
+				c.Response().Header().Set(TraceIDHeader, spanContext.TraceID().String())
+			}
+
+			c.SetRequest(req.WithContext(ctx))
+			err := next(c)
+
+			code := status(c, err)
+			span.SetAttributes(attribute.Int("http.response.status_code", code))
+			if code >= 500 {
+				span.SetStatus(codes.Error, "")
+				if err != nil {
+					span.RecordError(err)
+				}
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package poolgraph
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+// Crawler periodically reloads a Graph from a graph source.
+type Crawler struct {
+	source   domain.TheGraphServiceInterface
+	graph    *Graph
+	interval time.Duration
+}
+
+func NewCrawler(source domain.TheGraphServiceInterface, graph *Graph, interval time.Duration) *Crawler {
+	return &Crawler{
+		source:   source,
+		graph:    graph,
+		interval: interval,
+	}
+}
+
+// Run crawls immediately and then on every interval until ctx is done.
+func (c *Crawler) Run(ctx context.Context) {
+	c.Refresh(ctx)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Refresh(ctx)
+		}
+	}
+}
+
+// Refresh performs one crawl. A partial crawl only replaces the graph while
+// it is still empty, so one failing endpoint does not wipe known pools.
+func (c *Crawler) Refresh(ctx context.Context) {
+	pools, err := c.source.GetAllPools(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Pool crawl failed", "error", err, "pools", len(pools))
+		if c.graph.Size() > 0 || len(pools) == 0 {
+			return
+		}
+	}
+
+	c.graph.Replace(pools)
+	slog.InfoContext(ctx, "Pool graph refreshed", "pools", len(pools))
+}
@@ -0,0 +1,114 @@
+package poolgraph
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+// Graph is an in-memory view of every pool discovered by the crawler,
+// indexed by pool address and by token so pair lookups avoid the subgraph.
+type Graph struct {
+	mu        sync.RWMutex
+	pools     map[string]*domain.PoolData
+	byToken   map[string][]*domain.PoolData
+	updatedAt time.Time
+}
+
+func NewGraph() *Graph {
+	return &Graph{
+		pools:   make(map[string]*domain.PoolData),
+		byToken: make(map[string][]*domain.PoolData),
+	}
+}
+
+// Replace swaps the whole graph for a freshly crawled pool set.
+func (g *Graph) Replace(pools []*domain.PoolData) {
+	byAddress := make(map[string]*domain.PoolData, len(pools))
+	byToken := make(map[string][]*domain.PoolData)
+
+	for _, pool := range pools {
+		byAddress[strings.ToLower(pool.ID)] = pool
+		token0 := strings.ToLower(pool.Token0)
+		token1 := strings.ToLower(pool.Token1)
+		byToken[token0] = append(byToken[token0], pool)
+		byToken[token1] = append(byToken[token1], pool)
+	}
+
+	for _, tokenPools := range byToken {
+		sortByTVL(tokenPools)
+	}
+
+	g.mu.Lock()
+	g.pools = byAddress
+	g.byToken = byToken
+	g.updatedAt = time.Now()
+	g.mu.Unlock()
+}
+
+func (g *Graph) Pool(address string) (*domain.PoolData, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	pool, ok := g.pools[strings.ToLower(address)]
+	return pool, ok
+}
+
+// Pools returns every pool, deepest first.
+func (g *Graph) Pools() []*domain.PoolData {
+	g.mu.RLock()
+	pools := make([]*domain.PoolData, 0, len(g.pools))
+	for _, pool := range g.pools {
+		pools = append(pools, pool)
+	}
+	g.mu.RUnlock()
+
+	sortByTVL(pools)
+	return pools
+}
+
+// PoolsForToken returns the pools holding the token, deepest first.
+func (g *Graph) PoolsForToken(token string) []*domain.PoolData {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return append([]*domain.PoolData(nil), g.byToken[strings.ToLower(token)]...)
+}
+
+// PoolsForPair returns the pools trading tokenA against tokenB in either
+// order, deepest first.
+func (g *Graph) PoolsForPair(tokenA, tokenB string) []*domain.PoolData {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var pools []*domain.PoolData
+	for _, pool := range g.byToken[strings.ToLower(tokenA)] {
+		if strings.EqualFold(pool.Token0, tokenB) || strings.EqualFold(pool.Token1, tokenB) {
+			pools = append(pools, pool)
+		}
+	}
+	return pools
+}
+
+func (g *Graph) Size() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return len(g.pools)
+}
+
+func (g *Graph) UpdatedAt() time.Time {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.updatedAt
+}
+
+func sortByTVL(pools []*domain.PoolData) {
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].ReserveUSD > pools[j].ReserveUSD
+	})
+}
@@ -0,0 +1,244 @@
+package stream
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/gorilla/websocket"
+)
+
+type client struct {
+	hub    *Hub
+	conn   *websocket.Conn
+	send   chan domain.StreamMessage
+	done   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	subs      map[string]*subscription
+	dropped   int
+	closeOnce sync.Once
+}
+
+type subscription struct {
+	req  domain.QuoteRequest
+	last string
+}
+
+func newClient(hub *Hub, conn *websocket.Conn) *client {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &client{
+		hub:    hub,
+		conn:   conn,
+		send:   make(chan domain.StreamMessage, hub.options.SendBuffer),
+		done:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+		subs:   make(map[string]*subscription),
+	}
+}
+
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		c.cancel()
+		close(c.done)
+	})
+}
+
+func (c *client) requests() []domain.QuoteRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	requests := make([]domain.QuoteRequest, 0, len(c.subs))
+	for _, sub := range c.subs {
+		requests = append(requests, sub.req)
+	}
+	return requests
+}
+
+// update pushes the results that changed since the last push. Tuples
+// subscribed after the results were requested have none yet; they got
+// their first quote on subscribing and are refreshed on the next block.
+func (c *client) update(blockNumber uint64, resultFor func(domain.QuoteRequest) (domain.BatchQuoteResult, bool)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, sub := range c.subs {
+		result, ok := resultFor(sub.req)
+		if !ok {
+			continue
+		}
+		current := fingerprint(result)
+		if current == sub.last {
+			continue
+		}
+
+		if c.enqueueLocked(resultMessage(id, blockNumber, result)) {
+			sub.last = current
+		}
+	}
+}
+
+// enqueueLocked queues msg without blocking the hub. When the buffer is
+// full the update is dropped and the subscription stays dirty, so the
+// client receives the latest state once it catches up.
+func (c *client) enqueueLocked(msg domain.StreamMessage) bool {
+	select {
+	case c.send <- msg:
+		c.dropped = 0
+		return true
+	default:
+		c.dropped++
+		if c.hub.options.MaxDroppedUpdates > 0 && c.dropped > c.hub.options.MaxDroppedUpdates {
+			c.close()
+		}
+		return false
+	}
+}
+
+func (c *client) enqueue(msg domain.StreamMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.enqueueLocked(msg)
+}
+
+func (c *client) readLoop() {
+	defer c.hub.wg.Done()
+	defer c.close()
+
+	c.conn.SetReadLimit(maxFrameSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var req domain.StreamRequest
+		if err := c.conn.ReadJSON(&req); err != nil {
+			return
+		}
+
+		switch req.Type {
+		case domain.StreamSubscribe:
+			c.subscribe(req)
+		case domain.StreamUnsubscribe:
+			c.unsubscribe(req)
+		default:
+			c.enqueue(errorMessage(req.ID, domain.NewError(domain.CodeInvalidInput, "unknown message type: "+req.Type, nil)))
+		}
+	}
+}
+
+func (c *client) subscribe(req domain.StreamRequest) {
+	quoteReq := domain.QuoteRequest{From: req.From, To: req.To, Amount: req.Amount}
+	if req.ID == "" || quoteReq.From == "" || quoteReq.To == "" || quoteReq.Amount == "" {
+		c.enqueue(errorMessage(req.ID, domain.NewError(domain.CodeInvalidInput, "id, from, to and amount are required", nil)))
+		return
+	}
+
+	c.mu.Lock()
+	_, exists := c.subs[req.ID]
+	if !exists && c.hub.options.MaxSubscriptions > 0 && len(c.subs) >= c.hub.options.MaxSubscriptions {
+		c.mu.Unlock()
+		c.enqueue(errorMessage(req.ID, domain.NewError(domain.CodeInvalidInput, "subscription limit reached", nil)))
+		return
+	}
+	sub := &subscription{req: quoteReq}
+	c.subs[req.ID] = sub
+	c.enqueueLocked(domain.StreamMessage{Type: domain.StreamSubscribed, ID: req.ID})
+	c.mu.Unlock()
+
+	// Send the current quote right away rather than waiting for a block.
+	response, err := c.hub.usecase.BatchQuote(c.ctx, domain.BatchRequest{Quotes: []domain.QuoteRequest{quoteReq}})
+	if err != nil {
+		c.enqueue(errorMessage(req.ID, err))
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.subs[req.ID] != sub {
+		return
+	}
+	result := response.Quotes[0]
+	if c.enqueueLocked(resultMessage(req.ID, response.BlockNumber, result)) {
+		sub.last = fingerprint(result)
+	}
+}
+
+func (c *client) unsubscribe(req domain.StreamRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.subs, req.ID)
+	c.enqueueLocked(domain.StreamMessage{Type: domain.StreamUnsubscribed, ID: req.ID})
+}
+
+func (c *client) writeLoop() {
+	defer c.hub.wg.Done()
+	defer c.hub.remove(c)
+	defer c.conn.Close()
+
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			c.drain()
+			return
+		case msg := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(c.hub.options.WriteTimeout))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				c.close()
+				return
+			}
+		case <-ping.C:
+			c.conn.SetWriteDeadline(time.Now().Add(c.hub.options.WriteTimeout))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+// drain flushes already queued messages and then closes the connection
+// with a going-away frame so clients know to reconnect elsewhere.
+func (c *client) drain() {
+	c.conn.SetWriteDeadline(time.Now().Add(c.hub.options.WriteTimeout))
+
+	for {
+		select {
+		case msg := <-c.send:
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		default:
+			c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server closing"))
+			return
+		}
+	}
+}
+
+func resultMessage(id string, blockNumber uint64, result domain.BatchQuoteResult) domain.StreamMessage {
+	if result.Error != nil {
+		return domain.StreamMessage{Type: domain.StreamError, ID: id, BlockNumber: blockNumber, Error: result.Error}
+	}
+	return domain.StreamMessage{Type: domain.StreamQuote, ID: id, BlockNumber: blockNumber, Quote: result.Result}
+}
+
+func errorMessage(id string, err error) domain.StreamMessage {
+	response := domain.NewErrorResponse(err)
+	if response.Code >= http.StatusInternalServerError {
+		slog.Warn("Subscription failed", "id", id, "error", err)
+	}
+	return domain.StreamMessage{Type: domain.StreamError, ID: id, Error: &response}
+}
@@ -0,0 +1,201 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	pongWait     = 60 * time.Second
+	pingInterval = 45 * time.Second
+	maxFrameSize = 4096
+	// maxBatchSize matches the per-list limit of domain.BatchRequest.
+	maxBatchSize = 100
+)
+
+type Options struct {
+	// MaxSubscriptions caps subscriptions per connection.
+	MaxSubscriptions int
+	// SendBuffer is the number of outgoing messages queued per connection.
+	SendBuffer int
+	// MaxDroppedUpdates is how many consecutive updates a client may miss
+	// because its buffer was full before it is disconnected.
+	MaxDroppedUpdates int
+	WriteTimeout      time.Duration
+}
+
+// Hub serves quote subscriptions over WebSocket. On every new block it
+// re-quotes each distinct subscribed tuple once and pushes the result to the
+// clients whose quote changed.
+type Hub struct {
+	usecase  domain.UsecaseInterface
+	blocks   domain.BlockSourceInterface
+	options  Options
+	upgrader websocket.Upgrader
+
+	mu      sync.Mutex
+	clients map[*client]struct{}
+	closing bool
+	wg      sync.WaitGroup
+}
+
+func NewHub(usecase domain.UsecaseInterface, blocks domain.BlockSourceInterface, options Options) *Hub {
+	return &Hub{
+		usecase: usecase,
+		blocks:  blocks,
+		options: options,
+		upgrader: websocket.Upgrader{
+			// The API is cookie-less, so cross-origin dashboards are fine.
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		clients: make(map[*client]struct{}),
+	}
+}
+
+// Run pushes updates on every new block until ctx is done.
+func (h *Hub) Run(ctx context.Context) {
+	blocks, unsubscribe := h.blocks.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case blockNumber := <-blocks:
+			h.broadcast(ctx, blockNumber)
+		}
+	}
+}
+
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	closing := h.closing
+	h.mu.Unlock()
+	if closing {
+		http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written an error response.
+		return
+	}
+
+	c := newClient(h, conn)
+
+	h.mu.Lock()
+	if h.closing {
+		h.mu.Unlock()
+		conn.Close()
+		return
+	}
+	h.clients[c] = struct{}{}
+	h.wg.Add(2)
+	h.mu.Unlock()
+
+	go c.writeLoop()
+	go c.readLoop()
+}
+
+// Shutdown stops accepting connections, sends every client a going-away
+// close frame and waits for their goroutines to exit or ctx to expire.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closing = true
+	for c := range h.clients {
+		c.close()
+	}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *Hub) remove(c *client) {
+	h.mu.Lock()
+	delete(h.clients, c)
+	h.mu.Unlock()
+}
+
+func (h *Hub) broadcast(ctx context.Context, blockNumber uint64) {
+	h.mu.Lock()
+	clients := make([]*client, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	// Collapse identical tuples across all clients into one quote each.
+	index := make(map[string]int)
+	var requests []domain.QuoteRequest
+	for _, c := range clients {
+		for _, req := range c.requests() {
+			key := requestKey(req)
+			if _, ok := index[key]; !ok {
+				index[key] = len(requests)
+				requests = append(requests, req)
+			}
+		}
+	}
+
+	if len(requests) == 0 {
+		return
+	}
+
+	// Pin every chunk to the block, so all results are one snapshot.
+	blockCtx := domain.WithBlockNumber(ctx, blockNumber)
+	results := make([]domain.BatchQuoteResult, 0, len(requests))
+	for start := 0; start < len(requests); start += maxBatchSize {
+		end := min(start+maxBatchSize, len(requests))
+
+		response, err := h.usecase.BatchQuote(blockCtx, domain.BatchRequest{Quotes: requests[start:end]})
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to refresh streamed quotes", "block", blockNumber, "error", err)
+			return
+		}
+		results = append(results, response.Quotes...)
+	}
+
+	for _, c := range clients {
+		c.update(blockNumber, func(req domain.QuoteRequest) (domain.BatchQuoteResult, bool) {
+			i, ok := index[requestKey(req)]
+			if !ok {
+				return domain.BatchQuoteResult{}, false
+			}
+			return results[i], true
+		})
+	}
+}
+
+func requestKey(req domain.QuoteRequest) string {
+	return strings.ToUpper(req.From) + "|" + strings.ToUpper(req.To) + "|" + req.Amount
+}
+
+// fingerprint identifies the content of a result so unchanged quotes are
+// not pushed again.
+func fingerprint(result domain.BatchQuoteResult) string {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,103 @@
+package stream
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+// blockingUsecase quotes every request into its own To token, holding each
+// BatchQuote until release is closed.
+type blockingUsecase struct {
+	domain.UsecaseInterface
+	started chan struct{}
+	release chan struct{}
+}
+
+func (u *blockingUsecase) BatchQuote(_ context.Context, req domain.BatchRequest) (domain.BatchResponse, error) {
+	u.started <- struct{}{}
+	<-u.release
+
+	response := domain.BatchResponse{BlockNumber: 1}
+	for _, quoteReq := range req.Quotes {
+		response.Quotes = append(response.Quotes, domain.BatchQuoteResult{Result: &domain.QuoteResponse{
+			FromToken: quoteReq.From,
+			ToToken:   quoteReq.To,
+			ToAmount:  "1",
+		}})
+	}
+	return response, nil
+}
+
+func TestBroadcastSkipsSubscriptionsAddedDuringRefresh(t *testing.T) {
+	usecase := &blockingUsecase{started: make(chan struct{}), release: make(chan struct{})}
+	hub := NewHub(usecase, nil, Options{SendBuffer: 10})
+
+	c := newClient(hub, nil)
+	c.subs["usdc"] = &subscription{req: domain.QuoteRequest{From: "WETH", To: "USDC", Amount: "1"}}
+	hub.clients[c] = struct{}{}
+
+	done := make(chan struct{})
+	go func() {
+		hub.broadcast(context.Background(), 1)
+		close(done)
+	}()
+
+	<-usecase.started
+	c.mu.Lock()
+	c.subs["dai"] = &subscription{req: domain.QuoteRequest{From: "WETH", To: "DAI", Amount: "1"}}
+	c.mu.Unlock()
+	close(usecase.release)
+	<-done
+
+	if len(c.send) != 1 {
+		t.Fatalf("queued %d messages, want 1", len(c.send))
+	}
+	msg := <-c.send
+	if msg.ID != "usdc" || msg.Quote == nil || msg.Quote.ToToken != "USDC" {
+		t.Errorf("message = %+v, want the USDC quote for subscription usdc", msg)
+	}
+}
+
+// pinnedUsecase records the block each BatchQuote call is pinned to and
+// answers at that block.
+type pinnedUsecase struct {
+	domain.UsecaseInterface
+	blocks []uint64
+}
+
+func (u *pinnedUsecase) BatchQuote(ctx context.Context, req domain.BatchRequest) (domain.BatchResponse, error) {
+	blockNumber, _ := domain.BlockNumberFromContext(ctx)
+	u.blocks = append(u.blocks, blockNumber)
+
+	response := domain.BatchResponse{BlockNumber: blockNumber}
+	for _, quoteReq := range req.Quotes {
+		response.Quotes = append(response.Quotes, domain.BatchQuoteResult{Result: &domain.QuoteResponse{ToAmount: quoteReq.Amount}})
+	}
+	return response, nil
+}
+
+func TestBroadcastPinsEveryChunkToTheBlock(t *testing.T) {
+	usecase := &pinnedUsecase{}
+	hub := NewHub(usecase, nil, Options{SendBuffer: 2 * maxBatchSize})
+
+	c := newClient(hub, nil)
+	for i := range maxBatchSize + 1 {
+		id := strconv.Itoa(i)
+		c.subs[id] = &subscription{req: domain.QuoteRequest{From: "WETH", To: "USDC", Amount: id}}
+	}
+	hub.clients[c] = struct{}{}
+
+	hub.broadcast(context.Background(), 42)
+
+	if len(usecase.blocks) != 2 || usecase.blocks[0] != 42 || usecase.blocks[1] != 42 {
+		t.Errorf("chunks pinned to blocks %v, want two at block 42", usecase.blocks)
+	}
+	for len(c.send) > 0 {
+		if msg := <-c.send; msg.BlockNumber != 42 {
+			t.Errorf("message %s labelled block %d, want 42", msg.ID, msg.BlockNumber)
+		}
+	}
+}
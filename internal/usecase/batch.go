@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+// batchConcurrency bounds how many batch items are evaluated at once.
+const batchConcurrency = 8
+
+type BatchUsecase struct {
+	ethereumService domain.EthereumServiceInterface
+	graphService    domain.TheGraphServiceInterface
+	poolGraph       domain.PoolGraphInterface
+	settings        *Settings
+	gasSource       domain.GasPriceSource
+}
+
+func NewBatchUsecase(ethereumService domain.EthereumServiceInterface, graphService domain.TheGraphServiceInterface, poolGraph domain.PoolGraphInterface, settings *Settings, gasSource domain.GasPriceSource) *BatchUsecase {
+	return &BatchUsecase{
+		ethereumService: ethereumService,
+		graphService:    graphService,
+		poolGraph:       poolGraph,
+		settings:        settings,
+		gasSource:       gasSource,
+	}
+}
+
+// BatchQuote evaluates every quote and estimate against one block, the one
+// ctx is pinned to or else the current head. A failed item only fails its
+// own slot in the response.
+func (u *BatchUsecase) BatchQuote(ctx context.Context, req domain.BatchRequest) (domain.BatchResponse, error) {
+	if len(req.Quotes) == 0 && len(req.Estimates) == 0 {
+		return domain.BatchResponse{}, domain.NewError(domain.CodeInvalidInput, "batch must contain at least one quote or estimate", nil)
+	}
+
+	ctx, err := pinBlock(ctx, u.ethereumService)
+	if err != nil {
+		return domain.BatchResponse{}, err
+	}
+	blockNumber, _ := domain.BlockNumberFromContext(ctx)
+
+	snapshot := newSnapshotService(u.ethereumService)
+	var gasSource domain.GasPriceSource
+	if u.gasSource != nil {
+		gasSource = &onceGasSource{next: u.gasSource}
+	}
+	quoteUsecase := NewQuoteUsecase(snapshot, u.graphService, u.poolGraph, u.settings, gasSource)
+	estimateUsecase := NewEstimateUsecase(snapshot)
+
+	response := domain.BatchResponse{
+		BlockNumber: blockNumber,
+		Quotes:      make([]domain.BatchQuoteResult, len(req.Quotes)),
+		Estimates:   make([]domain.BatchEstimateResult, len(req.Estimates)),
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batchConcurrency)
+	run := func(fn func()) {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn()
+		}()
+	}
+
+	for i, item := range req.Quotes {
+		run(func() {
+			if err := validateQuoteRequest(item); err != nil {
+				response.Quotes[i] = domain.BatchQuoteResult{Error: batchError(ctx, err)}
+				return
+			}
+			result, err := quoteUsecase.Quote(ctx, item)
+			if err != nil {
+				response.Quotes[i] = domain.BatchQuoteResult{Error: batchError(ctx, err)}
+				return
+			}
+			response.Quotes[i] = domain.BatchQuoteResult{Result: &result}
+		})
+	}
+
+	for i, item := range req.Estimates {
+		run(func() {
+			if err := validateEstimateRequest(item); err != nil {
+				response.Estimates[i] = domain.BatchEstimateResult{Error: batchError(ctx, err)}
+				return
+			}
+			result, err := estimateUsecase.Estimate(ctx, item)
+			if err != nil {
+				response.Estimates[i] = domain.BatchEstimateResult{Error: batchError(ctx, err)}
+				return
+			}
+			response.Estimates[i] = domain.BatchEstimateResult{Result: &result}
+		})
+	}
+
+	wg.Wait()
+
+	return response, nil
+}
+
+func validateQuoteRequest(req domain.QuoteRequest) error {
+	if req.From == "" || req.To == "" || req.Amount == "" {
+		return domain.NewError(domain.CodeInvalidInput, "from, to and amount are required", nil)
+	}
+	return nil
+}
+
+func validateEstimateRequest(req domain.EstimateRequest) error {
+	if req.Pool == "" || req.Src == "" || req.Dst == "" || req.SrcAmount == "" {
+		return domain.NewError(domain.CodeInvalidInput, "pool, src, dst and src_amount are required", nil)
+	}
+	return nil
+}
+
+// batchError reports a failed item. Upstream failures are logged with their
+// cause, which the response leaves out.
+func batchError(ctx context.Context, err error) *domain.ErrorResponse {
+	response := domain.NewErrorResponse(err)
+	if response.Code >= http.StatusInternalServerError {
+		slog.WarnContext(ctx, "Batch item failed", "error", err)
+	}
+	return &response
+}
+
+// onceGasSource reads the fees once, so every quote of a batch is netted at
+// the same gas price.
+type onceGasSource struct {
+	next domain.GasPriceSource
+
+	once sync.Once
+	fees *domain.GasFees
+	err  error
+}
+
+func (s *onceGasSource) GasFees(ctx context.Context) (*domain.GasFees, error) {
+	s.once.Do(func() {
+		s.fees, s.err = s.next.GasFees(ctx)
+	})
+	return s.fees, s.err
+}
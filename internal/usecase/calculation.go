@@ -5,6 +5,8 @@ import (
 	"math/big"
 	"strings"
 	"sync"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
 )
 
 var (
@@ -19,33 +21,33 @@ func (u *EstimateUsecase) parseAmount(amountStr string) (*big.Int, error) {
 	amountStr = strings.TrimSpace(amountStr)
 
 	if amountStr == "" {
-		return nil, fmt.Errorf("amount cannot be empty")
+		return nil, domain.NewError(domain.CodeInvalidInput, "amount cannot be empty", nil)
 	}
 
 	amount, ok := new(big.Int).SetString(amountStr, 10)
 	if !ok {
-		return nil, fmt.Errorf("invalid amount format: %s", amountStr)
+		return nil, domain.NewError(domain.CodeInvalidInput, fmt.Sprintf("invalid amount format: %s", amountStr), nil)
 	}
 
 	if amount.Cmp(big.NewInt(0)) <= 0 {
-		return nil, fmt.Errorf("amount must be positive")
+		return nil, domain.NewError(domain.CodeInvalidInput, "amount must be positive", nil)
 	}
 
 	return amount, nil
 }
 
-func (u *EstimateUsecase) calculateAMMOutput(input, reserveIn, reserveOut *big.Int) (*big.Int, error) {
+func calculateAMMOutput(input, reserveIn, reserveOut *big.Int) (*big.Int, error) {
 	if input == nil || reserveIn == nil || reserveOut == nil {
-		return nil, fmt.Errorf("nil input/reserves")
+		return nil, domain.NewError(domain.CodeNoLiquidity, "nil input/reserves", nil)
 	}
 	if input.Sign() <= 0 {
-		return nil, fmt.Errorf("input amount must be positive")
+		return nil, domain.NewError(domain.CodeInvalidInput, "input amount must be positive", nil)
 	}
 	if reserveIn.Sign() <= 0 {
-		return nil, fmt.Errorf("invalid reserve in: must be positive")
+		return nil, domain.NewError(domain.CodeNoLiquidity, "invalid reserve in: must be positive", nil)
 	}
 	if reserveOut.Sign() <= 0 {
-		return nil, fmt.Errorf("invalid reserve out: must be positive")
+		return nil, domain.NewError(domain.CodeNoLiquidity, "invalid reserve out: must be positive", nil)
 	}
 
 	tmpInputWithFee := getTmp()
@@ -87,3 +89,25 @@ func putTmp(x *big.Int) {
 	x.SetInt64(0)
 	bigIntPool.Put(x)
 }
+
+// formatUnits renders a raw token amount as a decimal string in whole units,
+// e.g. 1500000 with 6 decimals becomes "1.5".
+func formatUnits(amount *big.Int, decimals uint8) string {
+	if decimals == 0 {
+		return amount.String()
+	}
+
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, frac := new(big.Int).QuoRem(new(big.Int).Abs(amount), divisor, new(big.Int))
+
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+
+	fracStr := strings.TrimRight(fmt.Sprintf("%0*s", int(decimals), frac.String()), "0")
+	if fracStr == "" {
+		return sign + whole.String()
+	}
+	return sign + whole.String() + "." + fracStr
+}
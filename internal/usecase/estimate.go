@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type EstimateUsecase struct {
@@ -20,33 +22,114 @@ func NewEstimateUsecase(ethereumService domain.EthereumServiceInterface) *Estima
 }
 
 func (u *EstimateUsecase) Estimate(ctx context.Context, req domain.EstimateRequest) (domain.EstimateResponse, error) {
-	poolReserves, err := u.ethereumService.GetPoolReserves(ctx, req.Pool)
+	ctx, span := tracing.Start(ctx, "EstimateUsecase.Estimate",
+		attribute.String("pool", req.Pool),
+		attribute.String("estimate.src", req.Src),
+		attribute.String("estimate.dst", req.Dst),
+	)
+	response, err := u.estimate(ctx, req)
+	tracing.End(span, err)
+
+	return response, err
+}
+
+func (u *EstimateUsecase) estimate(ctx context.Context, req domain.EstimateRequest) (domain.EstimateResponse, error) {
+	srcAmount, err := u.parseAmount(req.SrcAmount)
 	if err != nil {
-		return domain.EstimateResponse{}, fmt.Errorf("failed to get pool reserves: %w", err)
+		return domain.EstimateResponse{}, err
 	}
 
-	srcAmount, err := u.parseAmount(req.SrcAmount)
+	dstAmount, poolType, err := u.estimateExact(ctx, req.Pool, req.Src, req.Dst, srcAmount)
 	if err != nil {
-		return domain.EstimateResponse{}, fmt.Errorf("failed to parse source amount: %w", err)
+		return domain.EstimateResponse{}, err
+	}
+
+	dstAmountStr := dstAmount.String()
+
+	return domain.EstimateResponse{
+		DstAmount: dstAmountStr,
+		PoolType:  poolType,
+	}, nil
+}
+
+// SwapEstimate is Estimate with base-unit amounts. Unless ctx is already
+// pinned to a block, the pool is read at the current head.
+func (u *EstimateUsecase) SwapEstimate(ctx context.Context, pool, src, dst string, amountIn *big.Int) (domain.SwapEstimate, error) {
+	ctx, span := tracing.Start(ctx, "EstimateUsecase.SwapEstimate",
+		attribute.String("pool", pool),
+		attribute.String("estimate.src", src),
+		attribute.String("estimate.dst", dst),
+	)
+	estimate, err := u.swapEstimate(ctx, pool, src, dst, amountIn)
+	tracing.End(span, err)
+
+	return estimate, err
+}
+
+func (u *EstimateUsecase) swapEstimate(ctx context.Context, pool, src, dst string, amountIn *big.Int) (domain.SwapEstimate, error) {
+	if amountIn == nil || amountIn.Sign() <= 0 {
+		return domain.SwapEstimate{}, domain.NewError(domain.CodeInvalidInput, "amount must be positive", nil)
+	}
+
+	ctx, err := pinBlock(ctx, u.ethereumService)
+	if err != nil {
+		return domain.SwapEstimate{}, err
+	}
+
+	amountOut, poolType, err := u.estimateExact(ctx, pool, src, dst, amountIn)
+	if err != nil {
+		return domain.SwapEstimate{}, err
+	}
+
+	blockNumber, _ := domain.BlockNumberFromContext(ctx)
+
+	return domain.SwapEstimate{
+		Pool:        pool,
+		PoolType:    poolType,
+		Src:         src,
+		Dst:         dst,
+		AmountIn:    amountIn,
+		AmountOut:   amountOut,
+		BlockNumber: blockNumber,
+	}, nil
+}
+
+func (u *EstimateUsecase) estimateExact(ctx context.Context, pool, src, dst string, srcAmount *big.Int) (*big.Int, domain.PoolType, error) {
+	if strings.EqualFold(src, dst) {
+		return nil, "", domain.NewError(domain.CodeInvalidInput, "src and dst must be different tokens", nil)
+	}
+
+	// Probe the pool before reading it: getReserves on anything that is not
+	// a V2 pair either reverts or decodes into garbage.
+	poolType, err := u.ethereumService.DetectPoolType(ctx, pool)
+	if err != nil {
+		return nil, "", domain.NewUpstreamError("failed to detect pool type", err)
+	}
+	if poolType != domain.PoolTypeUniswapV2 {
+		return nil, "", domain.NewError(domain.CodeUnsupportedPool, fmt.Sprintf("pool %s is of type %s, only %s pools can be estimated", pool, poolType, domain.PoolTypeUniswapV2), nil)
+	}
+
+	poolReserves, err := u.ethereumService.GetPoolReserves(ctx, pool)
+	if err != nil {
+		return nil, "", domain.NewUpstreamError("failed to get pool reserves", err)
 	}
 
 	var reserveIn, reserveOut *big.Int
-	if strings.EqualFold(req.Src, poolReserves.Token0) {
+	switch {
+	case strings.EqualFold(src, poolReserves.Token0) && strings.EqualFold(dst, poolReserves.Token1):
 		reserveIn = poolReserves.Reserve0
 		reserveOut = poolReserves.Reserve1
-	} else {
+	case strings.EqualFold(src, poolReserves.Token1) && strings.EqualFold(dst, poolReserves.Token0):
 		reserveIn = poolReserves.Reserve1
 		reserveOut = poolReserves.Reserve0
+	default:
+		return nil, "", domain.NewError(domain.CodeInvalidInput, fmt.Sprintf("pool %s trades %s/%s, not %s/%s", pool, poolReserves.Token0, poolReserves.Token1, src, dst), nil)
 	}
 
-	dstAmount, err := u.calculateAMMOutput(srcAmount, reserveIn, reserveOut)
+	dstAmount, err := calculateAMMOutput(srcAmount, reserveIn, reserveOut)
 	if err != nil {
-		return domain.EstimateResponse{}, fmt.Errorf("failed to calculate AMM output: %w", err)
+		return nil, "", err
 	}
 
-	dstAmountStr := dstAmount.String()
-
-	return domain.EstimateResponse{
-		DstAmount: dstAmountStr,
-	}, nil
+	return dstAmount, poolType, nil
 }
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+// routeOverheadGas covers the transaction itself and the router around the
+// pool swaps.
+const routeOverheadGas = 50_000
+
+// poolSwapGas is what each pool on a route adds, by pool type. V3 swaps
+// cost more for their tick crossings.
+var poolSwapGas = map[domain.PoolType]uint64{
+	domain.PoolTypeUniswapV2: 70_000,
+	domain.PoolTypeUniswapV3: 100_000,
+}
+
+// routeGas estimates the gas of a swap through pools of the given types.
+// Unknown types are costed as V2 pools.
+func routeGas(poolTypes ...domain.PoolType) uint64 {
+	gas := uint64(routeOverheadGas)
+	for _, poolType := range poolTypes {
+		perPool, ok := poolSwapGas[poolType]
+		if !ok {
+			perPool = poolSwapGas[domain.PoolTypeUniswapV2]
+		}
+		gas += perPool
+	}
+	return gas
+}
+
+// applyGasCosts prices the gas of every quoted route in the output token
+// and sets the net outputs. It returns nil fees when gas cannot be priced,
+// and the quotes are then ranked by gross output.
+func (u *QuoteUsecase) applyGasCosts(ctx context.Context, quotes []domain.PoolQuote, toTokenInfo *domain.TokenInfo) *domain.GasFees {
+	if u.gasSource == nil || len(quotes) == 0 {
+		return nil
+	}
+
+	fees, err := u.gasSource.GasFees(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Ranking quotes by gross output", "reason", "gas fees unavailable", "error", err)
+		return nil
+	}
+
+	rate, err := u.weiRate(ctx, toTokenInfo)
+	if err != nil {
+		slog.WarnContext(ctx, "Ranking quotes by gross output", "reason", "gas cost not convertible", "error", err, "token", toTokenInfo.Address)
+		return nil
+	}
+
+	gasPrice := fees.GasPrice()
+	for i := range quotes {
+		poolType, err := u.ethereumService.DetectPoolType(ctx, quotes[i].Pool)
+		if err != nil {
+			poolType = domain.PoolTypeUnknown
+		}
+
+		gas := routeGas(poolType)
+		gasCost := new(big.Int).Mul(new(big.Int).SetUint64(gas), gasPrice)
+		gasCostOut := new(big.Rat).Mul(new(big.Rat).SetInt(gasCost), rate)
+		netAmountOut := new(big.Int).Sub(quotes[i].AmountOut, new(big.Int).Quo(gasCostOut.Num(), gasCostOut.Denom()))
+
+		quotes[i].GasEstimate = gas
+		quotes[i].GasCost = gasCost
+		quotes[i].NetAmountOut = netAmountOut
+	}
+
+	return fees
+}
+
+// weiRate is how many base units of token one wei of the native asset is
+// worth. The price comes from the deepest WETH pool of the crawled pool
+// graph, or from on-chain reserves when the graph has none.
+func (u *QuoteUsecase) weiRate(ctx context.Context, token *domain.TokenInfo) (*big.Rat, error) {
+	weth := u.settings.Tokens().Address("WETH")
+	if strings.EqualFold(token.Address, weth) {
+		return big.NewRat(1, 1), nil
+	}
+
+	price, err := u.graphWETHPrice(weth, token.Address)
+	if err != nil {
+		floatPrice, chainErr := u.tvlEstimator.relativePrice(ctx, weth, token.Address)
+		if chainErr != nil {
+			return nil, errors.Join(err, chainErr)
+		}
+		price = new(big.Rat).SetFloat64(floatPrice)
+		if price == nil {
+			return nil, fmt.Errorf("invalid WETH price %v", floatPrice)
+		}
+	}
+
+	// price is in whole units; scale it to base units per wei.
+	scale := new(big.Rat).SetFrac(
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(token.Decimals)), nil),
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil),
+	)
+	return price.Mul(price, scale), nil
+}
+
+// graphWETHPrice returns how many whole units of token one WETH is worth in
+// the deepest pool between them in the pool graph.
+func (u *QuoteUsecase) graphWETHPrice(weth, token string) (*big.Rat, error) {
+	if u.poolGraph == nil {
+		return nil, errors.New("no pool graph")
+	}
+
+	var deepest *domain.PoolData
+	for _, pool := range u.poolGraph.PoolsForPair(weth, token) {
+		if deepest == nil || pool.ReserveUSD > deepest.ReserveUSD {
+			deepest = pool
+		}
+	}
+	if deepest == nil {
+		return nil, fmt.Errorf("no WETH pool for %s in the pool graph", token)
+	}
+
+	wethReserve, tokenReserve := deepest.Reserve0, deepest.Reserve1
+	if !strings.EqualFold(deepest.Token0, weth) {
+		wethReserve, tokenReserve = tokenReserve, wethReserve
+	}
+
+	wethAmount, ok := new(big.Rat).SetString(wethReserve)
+	if !ok || wethAmount.Sign() <= 0 {
+		return nil, fmt.Errorf("pool %s has no WETH reserve", deepest.ID)
+	}
+	tokenAmount, ok := new(big.Rat).SetString(tokenReserve)
+	if !ok || tokenAmount.Sign() <= 0 {
+		return nil, fmt.Errorf("pool %s has no %s reserve", deepest.ID, token)
+	}
+
+	return tokenAmount.Quo(tokenAmount, wethAmount), nil
+}
+
+// rankAmount is what quotes are compared by: the net output when gas was
+// priced, the gross output otherwise.
+func rankAmount(quote *domain.PoolQuote) *big.Int {
+	if quote.NetAmountOut != nil {
+		return quote.NetAmountOut
+	}
+	return quote.AmountOut
+}
+
+func gasInfo(fees *domain.GasFees) *domain.GasInfo {
+	if fees == nil {
+		return nil
+	}
+	return &domain.GasInfo{
+		BaseFee:     fees.BaseFee.String(),
+		PriorityFee: fees.PriorityFee.String(),
+		GasPrice:    fees.GasPrice().String(),
+		BlockNumber: fees.BlockNumber,
+	}
+}
@@ -0,0 +1,376 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/ethereum"
+)
+
+const (
+	testWETH = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+	testUSDC = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	testDAI  = "0x6B175474E89094C44Da98b954EedeAC495271d0F"
+)
+
+var testTokens = map[string]*domain.TokenInfo{
+	strings.ToLower(testWETH): {Address: testWETH, Symbol: "WETH", Decimals: 18},
+	strings.ToLower(testUSDC): {Address: testUSDC, Symbol: "USDC", Decimals: 6},
+	strings.ToLower(testDAI):  {Address: testDAI, Symbol: "DAI", Decimals: 18},
+}
+
+// stubPool is a pool of stubChain. Its quote pays amountOut whatever the
+// input.
+type stubPool struct {
+	dex       string
+	address   string
+	reserves  *domain.PoolReserves
+	poolType  domain.PoolType
+	amountOut *big.Int
+}
+
+// stubChain serves token info, reserves, quotes and pool types from memory.
+// Pools without reserves are unknown to the factories, and pools with an
+// unknown type fail type detection.
+type stubChain struct {
+	domain.EthereumServiceInterface
+	pools []stubPool
+}
+
+func (c *stubChain) pool(address string) (stubPool, bool) {
+	for _, pool := range c.pools {
+		if strings.EqualFold(pool.address, address) {
+			return pool, true
+		}
+	}
+	return stubPool{}, false
+}
+
+func (c *stubChain) GetTokenInfo(_ context.Context, tokenAddress string) (*domain.TokenInfo, error) {
+	info, ok := testTokens[strings.ToLower(tokenAddress)]
+	if !ok {
+		return nil, errors.New("unknown token")
+	}
+	return info, nil
+}
+
+func (c *stubChain) FindAllPools(_ context.Context, tokenA, tokenB string) (map[string]string, error) {
+	pools := make(map[string]string)
+	for _, pool := range c.pools {
+		if pool.reserves == nil {
+			continue
+		}
+		token0, token1 := pool.reserves.Token0, pool.reserves.Token1
+		if (strings.EqualFold(token0, tokenA) && strings.EqualFold(token1, tokenB)) ||
+			(strings.EqualFold(token0, tokenB) && strings.EqualFold(token1, tokenA)) {
+			pools[pool.dex] = pool.address
+		}
+	}
+	return pools, nil
+}
+
+func (c *stubChain) GetPoolReserves(_ context.Context, poolAddress string) (*domain.PoolReserves, error) {
+	pool, ok := c.pool(poolAddress)
+	if !ok {
+		return nil, errors.New("unknown pool")
+	}
+	return pool.reserves, nil
+}
+
+func (c *stubChain) GetQuoteForPool(_ context.Context, poolAddress, _ string, _ *big.Int) (*big.Int, error) {
+	pool, ok := c.pool(poolAddress)
+	if !ok {
+		return nil, errors.New("unknown pool")
+	}
+	return new(big.Int).Set(pool.amountOut), nil
+}
+
+func (c *stubChain) DetectPoolType(_ context.Context, poolAddress string) (domain.PoolType, error) {
+	pool, ok := c.pool(poolAddress)
+	if !ok || pool.poolType == domain.PoolTypeUnknown {
+		return domain.PoolTypeUnknown, errors.New("unknown pool type")
+	}
+	return pool.poolType, nil
+}
+
+// stubPoolGraph is a crawled pool graph holding pools.
+type stubPoolGraph struct {
+	domain.PoolGraphInterface
+	pools []*domain.PoolData
+}
+
+func (g *stubPoolGraph) Pool(address string) (*domain.PoolData, bool) {
+	for _, pool := range g.pools {
+		if strings.EqualFold(pool.ID, address) {
+			return pool, true
+		}
+	}
+	return nil, false
+}
+
+func (g *stubPoolGraph) PoolsForPair(tokenA, tokenB string) []*domain.PoolData {
+	var pools []*domain.PoolData
+	for _, pool := range g.pools {
+		if (strings.EqualFold(pool.Token0, tokenA) && strings.EqualFold(pool.Token1, tokenB)) ||
+			(strings.EqualFold(pool.Token0, tokenB) && strings.EqualFold(pool.Token1, tokenA)) {
+			pools = append(pools, pool)
+		}
+	}
+	return pools
+}
+
+type stubGasSource struct {
+	fees *domain.GasFees
+	err  error
+}
+
+func (s stubGasSource) GasFees(context.Context) (*domain.GasFees, error) {
+	return s.fees, s.err
+}
+
+// tenGwei prices gas at 10 gwei: a 9 gwei base fee and a 1 gwei tip.
+var tenGwei = &domain.GasFees{BaseFee: big.NewInt(9e9), PriorityFee: big.NewInt(1e9), BlockNumber: 100}
+
+func units(amount int64, decimals int) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+}
+
+func newTestUsecase(chain *stubChain, graph domain.PoolGraphInterface, gasSource domain.GasPriceSource) *QuoteUsecase {
+	return NewQuoteUsecase(chain, nil, graph, NewSettings(0, ethereum.NewTokenRegistry(nil)), gasSource)
+}
+
+func TestWeiRate(t *testing.T) {
+	// 1 WETH = 2000 USDC on chain, 2500 USDC in the deeper graph pool.
+	chain := &stubChain{pools: []stubPool{{
+		dex:      "uniswap_v2",
+		address:  "0x01",
+		reserves: &domain.PoolReserves{Token0: testUSDC, Token1: testWETH, Reserve0: units(2_000_000, 6), Reserve1: units(1000, 18)},
+	}}}
+	graph := &stubPoolGraph{pools: []*domain.PoolData{
+		{ID: "0x02", Token0: testUSDC, Token1: testWETH, Reserve0: "100000", Reserve1: "10", ReserveUSD: 200_000},
+		{ID: "0x03", Token0: testUSDC, Token1: testWETH, Reserve0: "2500000", Reserve1: "1000", ReserveUSD: 5_000_000},
+		{ID: "0x04", Token0: testWETH, Token1: testDAI, Reserve0: "1000", Reserve1: "3000000", ReserveUSD: 6_000_000},
+	}}
+
+	tests := []struct {
+		name  string
+		graph domain.PoolGraphInterface
+		token string
+		want  *big.Rat
+	}{
+		{
+			name:  "WETH is the native asset",
+			graph: graph,
+			token: testWETH,
+			want:  big.NewRat(1, 1),
+		},
+		{
+			name:  "6-decimal token from the deepest graph pool",
+			graph: graph,
+			token: testUSDC,
+			want:  big.NewRat(2500, 1e12),
+		},
+		{
+			name:  "18-decimal token with WETH as token0",
+			graph: graph,
+			token: testDAI,
+			want:  big.NewRat(3000, 1),
+		},
+		{
+			name:  "on-chain price without a pool graph",
+			graph: nil,
+			token: testUSDC,
+			want:  big.NewRat(2000, 1e12),
+		},
+		{
+			name:  "on-chain price when the graph has no WETH pool",
+			graph: &stubPoolGraph{},
+			token: testUSDC,
+			want:  big.NewRat(2000, 1e12),
+		},
+		{
+			name:  "on-chain price when the graph pool has no reserves",
+			graph: &stubPoolGraph{pools: []*domain.PoolData{{ID: "0x05", Token0: testUSDC, Token1: testWETH, Reserve0: "0", Reserve1: "0"}}},
+			token: testUSDC,
+			want:  big.NewRat(2000, 1e12),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestUsecase(chain, tt.graph, nil)
+			rate, err := u.weiRate(context.Background(), testTokens[strings.ToLower(tt.token)])
+			if err != nil {
+				t.Fatalf("weiRate: %v", err)
+			}
+			if rate.Cmp(tt.want) != 0 {
+				t.Errorf("rate = %s, want %s", rate.RatString(), tt.want.RatString())
+			}
+		})
+	}
+}
+
+func TestWeiRateWithoutPrice(t *testing.T) {
+	u := newTestUsecase(&stubChain{}, &stubPoolGraph{}, nil)
+	if rate, err := u.weiRate(context.Background(), testTokens[strings.ToLower(testDAI)]); err == nil {
+		t.Errorf("rate = %s, want an error", rate.RatString())
+	}
+}
+
+func TestApplyGasCosts(t *testing.T) {
+	// 1 WETH = 2000 USDC, so 1e15 wei of gas costs 2 USDC.
+	graph := &stubPoolGraph{pools: []*domain.PoolData{
+		{ID: "0x03", Token0: testUSDC, Token1: testWETH, Reserve0: "2000000", Reserve1: "1000", ReserveUSD: 4_000_000},
+	}}
+	chain := &stubChain{pools: []stubPool{
+		{address: "0x0a", poolType: domain.PoolTypeUniswapV2},
+		{address: "0x0b", poolType: domain.PoolTypeUniswapV3},
+		{address: "0x0c", poolType: domain.PoolTypeUnknown},
+	}}
+	usdc := testTokens[strings.ToLower(testUSDC)]
+
+	tests := []struct {
+		name      string
+		gasSource domain.GasPriceSource
+		pool      string
+		wantGas   uint64
+		wantCost  *big.Int
+		wantNet   *big.Int
+	}{
+		{
+			name:      "V2 pool",
+			gasSource: stubGasSource{fees: tenGwei},
+			pool:      "0x0a",
+			wantGas:   120_000,
+			wantCost:  big.NewInt(1_200_000e9),
+			wantNet:   big.NewInt(100_000_000 - 2_400_000),
+		},
+		{
+			name:      "V3 pool",
+			gasSource: stubGasSource{fees: tenGwei},
+			pool:      "0x0b",
+			wantGas:   150_000,
+			wantCost:  big.NewInt(1_500_000e9),
+			wantNet:   big.NewInt(100_000_000 - 3_000_000),
+		},
+		{
+			name:      "undetected pool type is costed as V2",
+			gasSource: stubGasSource{fees: tenGwei},
+			pool:      "0x0c",
+			wantGas:   120_000,
+			wantCost:  big.NewInt(1_200_000e9),
+			wantNet:   big.NewInt(100_000_000 - 2_400_000),
+		},
+		{
+			name:      "gas fees unavailable",
+			gasSource: stubGasSource{err: errors.New("node down")},
+			pool:      "0x0a",
+		},
+		{
+			name: "no gas source",
+			pool: "0x0a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestUsecase(chain, graph, tt.gasSource)
+			quotes := []domain.PoolQuote{{Pool: tt.pool, AmountOut: units(100, 6)}}
+
+			fees := u.applyGasCosts(context.Background(), quotes, usdc)
+
+			quote := quotes[0]
+			if tt.wantNet == nil {
+				if fees != nil || quote.NetAmountOut != nil {
+					t.Errorf("fees = %v, net = %v, want gas left unpriced", fees, quote.NetAmountOut)
+				}
+				return
+			}
+			if fees != tenGwei {
+				t.Errorf("fees = %v, want %v", fees, tenGwei)
+			}
+			if quote.GasEstimate != tt.wantGas {
+				t.Errorf("gas estimate = %d, want %d", quote.GasEstimate, tt.wantGas)
+			}
+			if quote.GasCost.Cmp(tt.wantCost) != 0 {
+				t.Errorf("gas cost = %s, want %s", quote.GasCost, tt.wantCost)
+			}
+			if quote.NetAmountOut.Cmp(tt.wantNet) != 0 {
+				t.Errorf("net amount out = %s, want %s", quote.NetAmountOut, tt.wantNet)
+			}
+		})
+	}
+}
+
+func TestQuoteExactRanksByNetOutput(t *testing.T) {
+	// The V3 pool pays 0.5 USDC more but costs 0.6 USDC more gas.
+	chain := &stubChain{pools: []stubPool{
+		{
+			dex:       "uniswap_v2",
+			address:   "0x0a",
+			reserves:  &domain.PoolReserves{Token0: testUSDC, Token1: testWETH},
+			poolType:  domain.PoolTypeUniswapV2,
+			amountOut: big.NewInt(1_000_000_000),
+		},
+		{
+			dex:       "uniswap_v3",
+			address:   "0x0b",
+			reserves:  &domain.PoolReserves{Token0: testUSDC, Token1: testWETH},
+			poolType:  domain.PoolTypeUniswapV3,
+			amountOut: big.NewInt(1_000_500_000),
+		},
+	}}
+	graph := &stubPoolGraph{pools: []*domain.PoolData{
+		{ID: "0x0a", Token0: testUSDC, Token1: testWETH, Reserve0: "2000000", Reserve1: "1000", ReserveUSD: 4_000_000, DEX: "uniswap_v2"},
+		{ID: "0x0b", Token0: testUSDC, Token1: testWETH, Reserve0: "1000000", Reserve1: "500", ReserveUSD: 2_000_000, DEX: "uniswap_v3"},
+	}}
+	weth, usdc := testTokens[strings.ToLower(testWETH)], testTokens[strings.ToLower(testUSDC)]
+
+	tests := []struct {
+		name      string
+		gasSource domain.GasPriceSource
+		wantPool  string
+		wantNet   *big.Int
+	}{
+		{
+			name:      "net output",
+			gasSource: stubGasSource{fees: tenGwei},
+			wantPool:  "0x0a",
+			wantNet:   big.NewInt(997_600_000),
+		},
+		{
+			name:     "gross output without gas prices",
+			wantPool: "0x0b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestUsecase(chain, graph, tt.gasSource)
+
+			quote, err := u.quoteExact(context.Background(), weth, usdc, units(1, 18))
+			if err != nil {
+				t.Fatalf("quoteExact: %v", err)
+			}
+			if quote.Best.Pool != tt.wantPool {
+				t.Errorf("best pool = %s, want %s", quote.Best.Pool, tt.wantPool)
+			}
+			if len(quote.Quotes) != 2 {
+				t.Errorf("quoted %d pools, want 2", len(quote.Quotes))
+			}
+			if tt.wantNet == nil {
+				if quote.NetAmountOut != nil {
+					t.Errorf("net amount out = %s, want none", quote.NetAmountOut)
+				}
+				return
+			}
+			if quote.NetAmountOut.Cmp(tt.wantNet) != 0 {
+				t.Errorf("net amount out = %s, want %s", quote.NetAmountOut, tt.wantNet)
+			}
+		})
+	}
+}
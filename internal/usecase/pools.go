@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type PoolsUsecase struct {
+	ethereumService domain.EthereumServiceInterface
+	graphService    domain.TheGraphServiceInterface
+	poolGraph       domain.PoolGraphInterface
+	tvlEstimator    *TVLEstimator
+	settings        *Settings
+}
+
+func NewPoolsUsecase(ethereumService domain.EthereumServiceInterface, graphService domain.TheGraphServiceInterface, poolGraph domain.PoolGraphInterface, settings *Settings) *PoolsUsecase {
+	return &PoolsUsecase{
+		ethereumService: ethereumService,
+		graphService:    graphService,
+		poolGraph:       poolGraph,
+		tvlEstimator:    NewTVLEstimator(ethereumService, settings.Tokens()),
+		settings:        settings,
+	}
+}
+
+func (u *PoolsUsecase) Pools(ctx context.Context, req domain.PoolsRequest) (domain.PoolsResponse, error) {
+	var pools []*domain.PoolData
+	if req.Token != "" {
+		tokenAddr, err := resolveTokenOrAddress(u.settings.Tokens(), req.Token)
+		if err != nil {
+			return domain.PoolsResponse{}, err
+		}
+		pools = u.poolGraph.PoolsForToken(tokenAddr)
+	} else {
+		pools = u.poolGraph.Pools()
+	}
+
+	summaries := make([]domain.PoolSummary, 0, len(pools))
+	for _, pool := range pools {
+		if req.DEX != "" && !strings.EqualFold(pool.DEX, req.DEX) {
+			continue
+		}
+		summaries = append(summaries, u.buildPoolSummary(pool))
+	}
+
+	return domain.PoolsResponse{
+		Pools:     summaries,
+		Count:     len(summaries),
+		UpdatedAt: u.poolGraph.UpdatedAt(),
+	}, nil
+}
+
+// PoolDetail reads the pool's reserves on-chain and adds the subgraph's
+// TVL and volume figures when it knows the pool, estimating TVL from
+// reserves otherwise.
+func (u *PoolsUsecase) PoolDetail(ctx context.Context, req domain.PoolDetailRequest) (domain.PoolDetail, error) {
+	poolType, err := u.ethereumService.DetectPoolType(ctx, req.Address)
+	if err != nil {
+		return domain.PoolDetail{}, domain.NewUpstreamError("failed to detect pool type", err)
+	}
+	if poolType != domain.PoolTypeUniswapV2 {
+		return domain.PoolDetail{}, domain.NewError(domain.CodeUnsupportedPool, fmt.Sprintf("pool %s is of type %s, only %s pools can be inspected", req.Address, poolType, domain.PoolTypeUniswapV2), nil)
+	}
+
+	reserves, err := u.ethereumService.GetPoolReserves(ctx, req.Address)
+	if err != nil {
+		return domain.PoolDetail{}, domain.NewUpstreamError("failed to get pool reserves", err)
+	}
+
+	token0Info, err := u.ethereumService.GetTokenInfo(ctx, reserves.Token0)
+	if err != nil {
+		return domain.PoolDetail{}, domain.NewUpstreamError("failed to get token0 info", err)
+	}
+
+	token1Info, err := u.ethereumService.GetTokenInfo(ctx, reserves.Token1)
+	if err != nil {
+		return domain.PoolDetail{}, domain.NewUpstreamError("failed to get token1 info", err)
+	}
+
+	detail := domain.PoolDetail{
+		Address:     common.HexToAddress(req.Address).Hex(),
+		PoolType:    poolType,
+		Token0:      tokenFromInfo(token0Info),
+		Token1:      tokenFromInfo(token1Info),
+		BlockNumber: reserves.BlockNumber,
+		PoolInfo: domain.PoolInfo{
+			Reserve0:     formatUnits(reserves.Reserve0, token0Info.Decimals),
+			Reserve1:     formatUnits(reserves.Reserve1, token1Info.Decimals),
+			Token0Symbol: token0Info.Symbol,
+			Token1Symbol: token1Info.Symbol,
+		},
+	}
+
+	if poolData := u.subgraphPoolData(ctx, req.Address); poolData != nil {
+		detail.DEX = poolData.DEX
+		detail.PoolInfo.TVL = fmt.Sprintf("%.2f", poolData.ReserveUSD)
+		detail.PoolInfo.TVLSource = domain.TVLSourceSubgraph
+		detail.PoolInfo.Volume24h = fmt.Sprintf("%.2f", poolData.Volume24hUSD)
+		detail.PoolInfo.Fees24h = fmt.Sprintf("%.2f", poolData.Fees24hUSD)
+		detail.PoolInfo.IsActive = poolData.ReserveUSD >= u.settings.MinTVL()
+	} else if tvl, err := u.tvlEstimator.EstimatePoolTVL(ctx, reserves); err == nil {
+		detail.PoolInfo.TVL = fmt.Sprintf("%.2f", tvl)
+		detail.PoolInfo.TVLSource = domain.TVLSourceOnChain
+		detail.PoolInfo.IsActive = tvl >= u.settings.MinTVL()
+	} else {
+		slog.WarnContext(ctx, "Omitting pool TVL", "reason", "on-chain TVL unavailable", "error", err, "pool", req.Address)
+	}
+
+	return detail, nil
+}
+
+// subgraphPoolData looks the pool up in the crawled graph first and asks the
+// subgraph only for pools the crawl has not seen. Subgraph failures leave the
+// detail with on-chain data only.
+func (u *PoolsUsecase) subgraphPoolData(ctx context.Context, address string) *domain.PoolData {
+	if u.poolGraph != nil {
+		if poolData, ok := u.poolGraph.Pool(address); ok {
+			return poolData
+		}
+	}
+
+	if u.graphService == nil {
+		return nil
+	}
+
+	poolData, err := u.graphService.GetPoolData(ctx, address)
+	if err != nil {
+		slog.WarnContext(ctx, "Ignoring subgraph error", "reason", "pool lookup failed", "error", err, "pool", address)
+		return nil
+	}
+
+	return poolData
+}
+
+func (u *PoolsUsecase) buildPoolSummary(pool *domain.PoolData) domain.PoolSummary {
+	return domain.PoolSummary{
+		Address:      pool.ID,
+		DEX:          pool.DEX,
+		Token0:       pool.Token0,
+		Token1:       pool.Token1,
+		Token0Symbol: pool.Token0Symbol,
+		Token1Symbol: pool.Token1Symbol,
+		Reserve0:     pool.Reserve0,
+		Reserve1:     pool.Reserve1,
+		TVL:          fmt.Sprintf("%.2f", pool.ReserveUSD),
+	}
+}
+
+// resolveTokenOrAddress accepts either a registry symbol or a token address.
+func resolveTokenOrAddress(tokens *ethereum.TokenRegistry, token string) (string, error) {
+	if common.IsHexAddress(token) {
+		return token, nil
+	}
+
+	return resolveTokenAddress(tokens, token)
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+// priceIntermediates are tried in order to route a price through when the
+// pair has no pool of its own.
+var priceIntermediates = []string{"WETH", "USDC", "USDT", "DAI"}
+
+// Price reports the spot price of Base in Quote: every pool's mid price and
+// their average weighted by quote-side reserves, all read at one block.
+func (u *QuoteUsecase) Price(ctx context.Context, req domain.PriceRequest) (domain.PriceResponse, error) {
+	baseAddr, err := resolveTokenAddress(u.settings.Tokens(), req.Base)
+	if err != nil {
+		return domain.PriceResponse{}, err
+	}
+
+	quoteAddr, err := resolveTokenAddress(u.settings.Tokens(), req.Quote)
+	if err != nil {
+		return domain.PriceResponse{}, err
+	}
+
+	if strings.EqualFold(baseAddr, quoteAddr) {
+		return domain.PriceResponse{}, domain.NewError(domain.CodeInvalidInput, "base and quote must be different tokens", nil)
+	}
+
+	blockNumber, err := u.ethereumService.BlockNumber(ctx)
+	if err != nil {
+		return domain.PriceResponse{}, domain.NewUpstreamError("failed to get current block number", err)
+	}
+	ctx = domain.WithBlockNumber(ctx, blockNumber)
+
+	route := []string{strings.ToUpper(req.Base), strings.ToUpper(req.Quote)}
+	price, pools, err := u.pairPrice(ctx, baseAddr, quoteAddr)
+	if errors.Is(err, domain.ErrNoLiquidity) {
+		for _, symbol := range priceIntermediates {
+			intermediateAddr := u.settings.Tokens().Address(symbol)
+			if strings.EqualFold(intermediateAddr, baseAddr) || strings.EqualFold(intermediateAddr, quoteAddr) {
+				continue
+			}
+
+			firstPrice, firstPools, firstErr := u.pairPrice(ctx, baseAddr, intermediateAddr)
+			if firstErr != nil {
+				continue
+			}
+			secondPrice, secondPools, secondErr := u.pairPrice(ctx, intermediateAddr, quoteAddr)
+			if secondErr != nil {
+				continue
+			}
+
+			price = new(big.Rat).Mul(firstPrice, secondPrice)
+			pools = append(firstPools, secondPools...)
+			route = []string{route[0], symbol, route[1]}
+			err = nil
+			break
+		}
+		if err != nil {
+			return domain.PriceResponse{}, domain.NewError(domain.CodeNoLiquidity, fmt.Sprintf("no pools found for pair %s/%s, directly or via %s", req.Base, req.Quote, strings.Join(priceIntermediates, ", ")), nil)
+		}
+	}
+	if err != nil {
+		return domain.PriceResponse{}, err
+	}
+
+	return domain.PriceResponse{
+		BaseToken:   req.Base,
+		QuoteToken:  req.Quote,
+		Price:       price.FloatString(pricePrecision),
+		BlockNumber: blockNumber,
+		Route:       route,
+		Pools:       pools,
+	}, nil
+}
+
+// pairPrice aggregates the mid prices of every pool trading base against
+// quote. Each pool is weighted by its quote-side reserve, so a dust pool
+// with a stale price barely moves the result.
+func (u *QuoteUsecase) pairPrice(ctx context.Context, baseAddr, quoteAddr string) (*big.Rat, []domain.PoolPrice, error) {
+	candidates, err := u.candidatePools(ctx, baseAddr, quoteAddr)
+	if err != nil {
+		return nil, nil, domain.NewUpstreamError("failed to find pools", err)
+	}
+	if len(candidates) == 0 {
+		return nil, nil, domain.NewError(domain.CodeNoLiquidity, fmt.Sprintf("no pools found for pair %s/%s", baseAddr, quoteAddr), nil)
+	}
+
+	baseInfo, err := u.ethereumService.GetTokenInfo(ctx, baseAddr)
+	if err != nil {
+		return nil, nil, domain.NewUpstreamError("failed to get base token info", err)
+	}
+
+	quoteInfo, err := u.ethereumService.GetTokenInfo(ctx, quoteAddr)
+	if err != nil {
+		return nil, nil, domain.NewUpstreamError("failed to get quote token info", err)
+	}
+
+	dexNames := make([]string, 0, len(candidates))
+	for dexName := range candidates {
+		dexNames = append(dexNames, dexName)
+	}
+	sort.Strings(dexNames)
+
+	weightedSum := new(big.Rat)
+	totalWeight := new(big.Rat)
+	var pools []domain.PoolPrice
+	var lastErr error
+
+	for _, dexName := range dexNames {
+		poolAddress := candidates[dexName]
+
+		reserves, err := u.ethereumService.GetPoolReserves(ctx, poolAddress)
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping pool", "reason", "reserves unavailable", "error", err, "pool", poolAddress, "dex", dexName)
+			lastErr = err
+			continue
+		}
+
+		var reserveBase, reserveQuote *big.Int
+		switch {
+		case strings.EqualFold(reserves.Token0, baseAddr):
+			reserveBase, reserveQuote = reserves.Reserve0, reserves.Reserve1
+		case strings.EqualFold(reserves.Token1, baseAddr):
+			reserveBase, reserveQuote = reserves.Reserve1, reserves.Reserve0
+		default:
+			continue
+		}
+
+		price := midPrice(reserveBase, baseInfo.Decimals, reserveQuote, quoteInfo.Decimals)
+		if price == nil {
+			slog.DebugContext(ctx, "Skipping pool", "reason", "empty reserves", "pool", poolAddress, "dex", dexName)
+			continue
+		}
+
+		weight := new(big.Rat).SetFrac(reserveQuote, pow10(quoteInfo.Decimals))
+		weightedSum.Add(weightedSum, new(big.Rat).Mul(price, weight))
+		totalWeight.Add(totalWeight, weight)
+
+		pools = append(pools, domain.PoolPrice{
+			DEX:          dexName,
+			Pool:         poolAddress,
+			BaseToken:    baseInfo.Symbol,
+			QuoteToken:   quoteInfo.Symbol,
+			Price:        price.FloatString(pricePrecision),
+			BaseReserve:  formatUnits(reserveBase, baseInfo.Decimals),
+			QuoteReserve: formatUnits(reserveQuote, quoteInfo.Decimals),
+		})
+	}
+
+	if totalWeight.Sign() == 0 {
+		if lastErr != nil {
+			return nil, nil, domain.NewUpstreamError("failed to read any pool", lastErr)
+		}
+		return nil, nil, domain.NewError(domain.CodeNoLiquidity, fmt.Sprintf("no pool with reserves for pair %s/%s", baseInfo.Symbol, quoteInfo.Symbol), nil)
+	}
+
+	return new(big.Rat).Quo(weightedSum, totalWeight), pools, nil
+}
@@ -2,163 +2,468 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math/big"
 	"strconv"
 	"strings"
 
 	"github.com/DiDinar5/mini-dex-aggregator/domain"
 	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/ethereum"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/metrics"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type QuoteUsecase struct {
 	ethereumService domain.EthereumServiceInterface
 	graphService    domain.TheGraphServiceInterface
-	minTVL          float64
+	poolGraph       domain.PoolGraphInterface
+	tvlEstimator    *TVLEstimator
+	settings        *Settings
+	gasSource       domain.GasPriceSource
 }
 
-func NewQuoteUsecase(ethereumService domain.EthereumServiceInterface, graphService domain.TheGraphServiceInterface, minTVL float64) *QuoteUsecase {
+// NewQuoteUsecase builds the quote usecase. Without a gasSource quotes are
+// ranked by gross output.
+func NewQuoteUsecase(ethereumService domain.EthereumServiceInterface, graphService domain.TheGraphServiceInterface, poolGraph domain.PoolGraphInterface, settings *Settings, gasSource domain.GasPriceSource) *QuoteUsecase {
 	return &QuoteUsecase{
 		ethereumService: ethereumService,
 		graphService:    graphService,
-		minTVL:          minTVL,
+		poolGraph:       poolGraph,
+		tvlEstimator:    NewTVLEstimator(ethereumService, settings.Tokens()),
+		settings:        settings,
+		gasSource:       gasSource,
 	}
 }
 
+// errBelowMinTVL marks a pool skipped for being too shallow to quote.
+var errBelowMinTVL = errors.New("pool TVL below minimum")
+
 func (u *QuoteUsecase) Quote(ctx context.Context, req domain.QuoteRequest) (domain.QuoteResponse, error) {
-	fromSymbol := strings.ToUpper(req.From)
-	toSymbol := strings.ToUpper(req.To)
+	ctx, span := tracing.Start(ctx, "QuoteUsecase.Quote",
+		attribute.String("quote.from", req.From),
+		attribute.String("quote.to", req.To),
+		attribute.String("quote.amount", req.Amount),
+	)
+	response, err := u.quote(ctx, req)
+	tracing.End(span, err)
+
+	return response, err
+}
 
-	if fromSymbol == "ETH" {
-		fromSymbol = "WETH"
+func (u *QuoteUsecase) quote(ctx context.Context, req domain.QuoteRequest) (domain.QuoteResponse, error) {
+	fromTokenAddr, err := resolveTokenAddress(u.settings.Tokens(), req.From)
+	if err != nil {
+		return domain.QuoteResponse{}, err
 	}
-	if toSymbol == "ETH" {
-		toSymbol = "WETH"
+
+	toTokenAddr, err := resolveTokenAddress(u.settings.Tokens(), req.To)
+	if err != nil {
+		return domain.QuoteResponse{}, err
 	}
 
-	fromTokenAddr := ethereum.GetTokenAddress(fromSymbol)
-	if fromTokenAddr == "" {
-		return domain.QuoteResponse{}, fmt.Errorf("unknown token symbol: %s", req.From)
+	amountIn, err := u.parseAmount(req.Amount)
+	if err != nil {
+		return domain.QuoteResponse{}, err
 	}
 
-	toTokenAddr := ethereum.GetTokenAddress(toSymbol)
-	if toTokenAddr == "" {
-		return domain.QuoteResponse{}, fmt.Errorf("unknown token symbol: %s", req.To)
+	fromTokenInfo, toTokenInfo, err := u.tokenPair(ctx, fromTokenAddr, toTokenAddr)
+	if err != nil {
+		return domain.QuoteResponse{}, err
 	}
 
-	amountIn, err := u.parseAmount(req.Amount)
+	amountInWei := u.adjustForDecimals(amountIn, fromTokenInfo.Decimals)
+
+	swap, err := u.quoteExact(ctx, fromTokenInfo, toTokenInfo, amountInWei)
+	if err != nil {
+		return domain.QuoteResponse{}, err
+	}
+
+	allQuotes := make([]domain.DEXQuote, 0, len(swap.Quotes))
+	for _, quote := range swap.Quotes {
+		allQuotes = append(allQuotes, u.dexQuote(quote, toTokenInfo.Decimals))
+	}
+
+	response := domain.QuoteResponse{
+		FromToken:  req.From,
+		ToToken:    req.To,
+		FromAmount: req.Amount,
+		ToAmount:   u.adjustFromDecimals(swap.AmountOut, toTokenInfo.Decimals).String(),
+		BestQuote:  u.dexQuote(swap.Best, toTokenInfo.Decimals),
+		AllQuotes:  allQuotes,
+		Gas:        gasInfo(swap.GasFees),
+	}
+	if swap.NetAmountOut != nil {
+		response.NetToAmount = u.adjustFromDecimals(swap.NetAmountOut, toTokenInfo.Decimals).String()
+	}
+
+	return response, nil
+}
+
+// SwapQuote quotes amountIn base units of from into to, where either token
+// is a registry symbol or an address. Unless ctx is already pinned to a
+// block, every pool is read at the current head.
+func (u *QuoteUsecase) SwapQuote(ctx context.Context, from, to string, amountIn *big.Int) (domain.SwapQuote, error) {
+	ctx, span := tracing.Start(ctx, "QuoteUsecase.SwapQuote",
+		attribute.String("quote.from", from),
+		attribute.String("quote.to", to),
+		attribute.String("quote.amount", amountIn.String()),
+	)
+	quote, err := u.swapQuote(ctx, from, to, amountIn)
+	tracing.End(span, err)
+
+	return quote, err
+}
+
+func (u *QuoteUsecase) swapQuote(ctx context.Context, from, to string, amountIn *big.Int) (domain.SwapQuote, error) {
+	if amountIn == nil || amountIn.Sign() <= 0 {
+		return domain.SwapQuote{}, domain.NewError(domain.CodeInvalidInput, "amount must be positive", nil)
+	}
+
+	fromTokenAddr, err := resolveTokenOrAddress(u.settings.Tokens(), from)
+	if err != nil {
+		return domain.SwapQuote{}, err
+	}
+
+	toTokenAddr, err := resolveTokenOrAddress(u.settings.Tokens(), to)
+	if err != nil {
+		return domain.SwapQuote{}, err
+	}
+
+	ctx, err = pinBlock(ctx, u.ethereumService)
 	if err != nil {
-		return domain.QuoteResponse{}, fmt.Errorf("failed to parse amount: %w", err)
+		return domain.SwapQuote{}, err
 	}
 
+	fromTokenInfo, toTokenInfo, err := u.tokenPair(ctx, fromTokenAddr, toTokenAddr)
+	if err != nil {
+		return domain.SwapQuote{}, err
+	}
+
+	return u.quoteExact(ctx, fromTokenInfo, toTokenInfo, amountIn)
+}
+
+func (u *QuoteUsecase) tokenPair(ctx context.Context, fromTokenAddr, toTokenAddr string) (*domain.TokenInfo, *domain.TokenInfo, error) {
 	fromTokenInfo, err := u.ethereumService.GetTokenInfo(ctx, fromTokenAddr)
 	if err != nil {
-		return domain.QuoteResponse{}, fmt.Errorf("failed to get from token info: %w", err)
+		return nil, nil, domain.NewUpstreamError("failed to get from token info", err)
 	}
 
 	toTokenInfo, err := u.ethereumService.GetTokenInfo(ctx, toTokenAddr)
 	if err != nil {
-		return domain.QuoteResponse{}, fmt.Errorf("failed to get to token info: %w", err)
+		return nil, nil, domain.NewUpstreamError("failed to get to token info", err)
 	}
 
-	amountInWei := u.adjustForDecimals(amountIn, fromTokenInfo.Decimals)
+	return fromTokenInfo, toTokenInfo, nil
+}
 
-	pools, err := u.ethereumService.FindAllPools(ctx, fromTokenAddr, toTokenAddr)
+// quoteExact quotes amountIn base units on every candidate pool and picks
+// the one paying out the most.
+func (u *QuoteUsecase) quoteExact(ctx context.Context, fromTokenInfo, toTokenInfo *domain.TokenInfo, amountIn *big.Int) (domain.SwapQuote, error) {
+	fromTokenAddr, toTokenAddr := fromTokenInfo.Address, toTokenInfo.Address
+
+	pools, err := u.candidatePools(ctx, fromTokenAddr, toTokenAddr)
 	if err != nil {
-		return domain.QuoteResponse{}, fmt.Errorf("failed to find pools: %w", err)
+		return domain.SwapQuote{}, domain.NewUpstreamError("failed to find pools", err)
 	}
 
 	if len(pools) == 0 {
-		return domain.QuoteResponse{}, fmt.Errorf("no pools found for pair %s/%s", req.From, req.To)
+		return domain.SwapQuote{}, domain.NewError(domain.CodeNoLiquidity, fmt.Sprintf("no pools found for pair %s/%s", fromTokenInfo.Symbol, toTokenInfo.Symbol), nil)
 	}
 
-	poolDataMap := make(map[string]*domain.PoolData)
-	if u.graphService != nil {
-		graphPools, err := u.graphService.GetPoolsByTokenPair(ctx, fromTokenAddr, toTokenAddr)
-		if err == nil {
-			for _, poolData := range graphPools {
-				poolDataMap[strings.ToLower(poolData.ID)] = poolData
+	poolDataMap := u.fetchPoolData(ctx, fromTokenAddr, toTokenAddr, pools)
+
+	var allQuotes []domain.PoolQuote
+	var lastErr error
+
+	for dexName, poolAddress := range pools {
+		quote, err := u.quotePool(ctx, dexName, poolAddress, poolDataMap[strings.ToLower(poolAddress)], fromTokenAddr, amountIn)
+		if err != nil {
+			if !errors.Is(err, errBelowMinTVL) {
+				lastErr = err
 			}
+			continue
 		}
-		for _, poolAddress := range pools {
-			poolLower := strings.ToLower(poolAddress)
-			if _, exists := poolDataMap[poolLower]; !exists {
-				if poolData, err := u.graphService.GetPoolData(ctx, poolAddress); err == nil {
-					poolDataMap[poolLower] = poolData
-				}
-			}
+
+		allQuotes = append(allQuotes, quote)
+	}
+
+	// A small trade on a pricier route can end up worse than a cheaper
+	// route's, so rank by what the trader keeps after gas.
+	gasFees := u.applyGasCosts(ctx, allQuotes, toTokenInfo)
+
+	var bestQuote *domain.PoolQuote
+	for i := range allQuotes {
+		if bestQuote == nil || rankAmount(&allQuotes[i]).Cmp(rankAmount(bestQuote)) > 0 {
+			bestQuote = &allQuotes[i]
 		}
 	}
 
-	var allQuotes []domain.DEXQuote
-	var bestQuote *domain.DEXQuote
-	var bestAmount *big.Int
+	if bestQuote == nil {
+		// Pools that were skipped for low TVL are a liquidity problem; pools
+		// that could not be read are an upstream one.
+		if lastErr != nil {
+			return domain.SwapQuote{}, domain.NewUpstreamError("failed to get quotes from any pool", lastErr)
+		}
+		return domain.SwapQuote{}, domain.NewError(domain.CodeNoLiquidity, "no pool above the minimum TVL", nil)
+	}
 
-	for dexName, poolAddress := range pools {
-		poolLower := strings.ToLower(poolAddress)
-		poolData := poolDataMap[poolLower]
+	observeSpreads(allQuotes, gasFees != nil)
+
+	blockNumber, _ := domain.BlockNumberFromContext(ctx)
+
+	return domain.SwapQuote{
+		From:         *fromTokenInfo,
+		To:           *toTokenInfo,
+		AmountIn:     amountIn,
+		AmountOut:    bestQuote.AmountOut,
+		NetAmountOut: bestQuote.NetAmountOut,
+		Best:         *bestQuote,
+		Quotes:       allQuotes,
+		GasFees:      gasFees,
+		BlockNumber:  blockNumber,
+	}, nil
+}
 
-		if poolData != nil && poolData.ReserveUSD < u.minTVL {
-			continue
+// dexQuote reports a pool quote in whole units of the output token.
+func (u *QuoteUsecase) dexQuote(quote domain.PoolQuote, toDecimals uint8) domain.DEXQuote {
+	dexQuote := domain.DEXQuote{
+		DEX:         quote.DEX,
+		Pool:        quote.Pool,
+		ToAmount:    u.adjustFromDecimals(quote.AmountOut, toDecimals).String(),
+		GasEstimate: quote.GasEstimate,
+		PoolInfo:    quote.PoolInfo,
+	}
+	if quote.NetAmountOut != nil {
+		dexQuote.NetToAmount = u.adjustFromDecimals(quote.NetAmountOut, toDecimals).String()
+		dexQuote.GasCost = quote.GasCost.String()
+	}
+	return dexQuote
+}
+
+// quotePool prices amountIn on one pool. It fails with errBelowMinTVL for
+// pools too shallow to quote.
+func (u *QuoteUsecase) quotePool(ctx context.Context, dexName, poolAddress string, poolData *domain.PoolData, fromTokenAddr string, amountIn *big.Int) (domain.PoolQuote, error) {
+	ctx, span := tracing.Start(ctx, "QuoteUsecase.quotePool",
+		attribute.String("dex", dexName),
+		attribute.String("pool", poolAddress),
+	)
+
+	quote, err := u.evaluatePool(ctx, dexName, poolAddress, poolData, fromTokenAddr, amountIn)
+	switch {
+	case errors.Is(err, errBelowMinTVL):
+		span.SetAttributes(attribute.String("skip_reason", err.Error()))
+		tracing.End(span, nil)
+		slog.DebugContext(ctx, "Skipping pool", "reason", err, "pool", poolAddress, "dex", dexName)
+	case err != nil:
+		tracing.End(span, err)
+		slog.WarnContext(ctx, "Skipping pool", "reason", "quote failed", "error", err, "pool", poolAddress, "dex", dexName)
+	default:
+		tracing.End(span, nil)
+	}
+
+	return quote, err
+}
+
+func (u *QuoteUsecase) evaluatePool(ctx context.Context, dexName, poolAddress string, poolData *domain.PoolData, fromTokenAddr string, amountIn *big.Int) (domain.PoolQuote, error) {
+	// Prefer the subgraph TVL and fall back to pricing reserves on-chain
+	// so dust pools are still filtered when the subgraph is unavailable.
+	var poolInfo *domain.PoolInfo
+	if poolData != nil {
+		if poolData.ReserveUSD < u.settings.MinTVL() {
+			return domain.PoolQuote{}, errBelowMinTVL
 		}
+		poolInfo = u.buildPoolInfo(poolData)
+	} else if info, tvl, err := u.onChainPoolInfo(ctx, poolAddress); err == nil {
+		if tvl < u.settings.MinTVL() {
+			return domain.PoolQuote{}, errBelowMinTVL
+		}
+		poolInfo = info
+	} else {
+		slog.WarnContext(ctx, "Quoting pool without TVL check", "reason", "on-chain TVL unavailable", "error", err, "pool", poolAddress, "dex", dexName)
+	}
 
-		amountOut, err := u.ethereumService.GetQuoteForPool(ctx, poolAddress, fromTokenAddr, amountInWei)
-		if err != nil {
-			continue
+	amountOut, err := u.ethereumService.GetQuoteForPool(ctx, poolAddress, fromTokenAddr, amountIn)
+	if err != nil {
+		return domain.PoolQuote{}, err
+	}
+
+	return domain.PoolQuote{
+		DEX:       dexName,
+		Pool:      poolAddress,
+		AmountOut: amountOut,
+		PoolInfo:  poolInfo,
+	}, nil
+}
+
+// observeSpreads records how far the top pool is ahead of the runner-up,
+// by gross output and, when gas was priced, by output net of gas. The two
+// rankings may put different pools on top.
+func observeSpreads(quotes []domain.PoolQuote, gasPriced bool) {
+	if best, second := topTwo(quotes, func(quote *domain.PoolQuote) *big.Int { return quote.AmountOut }); second != nil && best.Sign() > 0 {
+		metrics.ObserveQuoteSpread(spreadBps(best, second))
+	}
+	if !gasPriced {
+		return
+	}
+	if best, second := topTwo(quotes, func(quote *domain.PoolQuote) *big.Int { return quote.NetAmountOut }); second != nil && best.Sign() > 0 {
+		metrics.ObserveQuoteNetSpread(spreadBps(best, second))
+	}
+}
+
+// topTwo returns the largest and second largest amount of the quotes;
+// second is nil for fewer than two quotes.
+func topTwo(quotes []domain.PoolQuote, amount func(*domain.PoolQuote) *big.Int) (best, second *big.Int) {
+	for i := range quotes {
+		value := amount(&quotes[i])
+		switch {
+		case best == nil || value.Cmp(best) > 0:
+			best, second = value, best
+		case second == nil || value.Cmp(second) > 0:
+			second = value
 		}
+	}
+	return best, second
+}
+
+// spreadBps is how much more best pays than second, in basis points of best.
+func spreadBps(best, second *big.Int) float64 {
+	spread := new(big.Rat).SetFrac(new(big.Int).Sub(best, second), best)
+	bps, _ := spread.Mul(spread, big.NewRat(10000, 1)).Float64()
+	return bps
+}
 
-		amountOutAdjusted := u.adjustFromDecimals(amountOut, toTokenInfo.Decimals)
+// resolveTokenAddress maps a token symbol onto its address. Native ETH is
+// traded through its wrapped form.
+func resolveTokenAddress(tokens *ethereum.TokenRegistry, symbol string) (string, error) {
+	upper := strings.ToUpper(symbol)
+	if upper == "ETH" {
+		upper = "WETH"
+	}
 
-		quote := domain.DEXQuote{
-			DEX:      dexName,
-			Pool:     poolAddress,
-			ToAmount: amountOutAdjusted.String(),
+	address := tokens.Address(upper)
+	if address == "" {
+		return "", domain.NewError(domain.CodeUnknownToken, fmt.Sprintf("unknown token symbol: %s", symbol), nil)
+	}
+
+	return address, nil
+}
+
+// candidatePools returns the pools to quote keyed by DEX: every factory
+// pool of the configured DEXes plus the crawled pool graph's pools for the
+// pair. The graph adds V2 forks whose factories are not configured, but
+// usually indexes only some DEXes, so it never replaces the factory lookup.
+// Graph pools are only quoted when they are V2 pairs, the one pool type
+// quotes are computed for.
+func (u *QuoteUsecase) candidatePools(ctx context.Context, fromTokenAddr, toTokenAddr string) (map[string]string, error) {
+	pools, err := u.ethereumService.FindAllPools(ctx, fromTokenAddr, toTokenAddr)
+
+	var graphPools []*domain.PoolData
+	if u.poolGraph != nil {
+		graphPools = u.poolGraph.PoolsForPair(fromTokenAddr, toTokenAddr)
+	}
+	if len(graphPools) == 0 {
+		return pools, err
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Quoting pool graph pools only", "reason", "factory lookup failed", "error", err, "from", fromTokenAddr, "to", toTokenAddr)
+		pools = nil
+	}
+
+	merged := make(map[string]string, len(pools)+len(graphPools))
+	seen := make(map[string]bool, len(pools)+len(graphPools))
+	for dexName, poolAddress := range pools {
+		merged[dexName] = poolAddress
+		seen[strings.ToLower(poolAddress)] = true
+	}
+	for _, poolData := range graphPools {
+		if seen[strings.ToLower(poolData.ID)] {
+			continue
 		}
+		seen[strings.ToLower(poolData.ID)] = true
 
-		if poolData != nil {
-			quote.PoolInfo = u.buildPoolInfo(poolData)
+		if poolType, err := u.ethereumService.DetectPoolType(ctx, poolData.ID); err != nil || poolType != domain.PoolTypeUniswapV2 {
+			slog.DebugContext(ctx, "Skipping pool", "reason", "not a V2 pair", "pool_type", poolType, "error", err, "pool", poolData.ID, "dex", poolData.DEX)
+			continue
 		}
 
-		allQuotes = append(allQuotes, quote)
+		key := poolData.DEX
+		if _, taken := merged[key]; taken || key == "" {
+			key = poolData.DEX + ":" + poolData.ID
+		}
+		merged[key] = poolData.ID
+	}
 
-		if bestAmount == nil || amountOut.Cmp(bestAmount) > 0 {
-			bestAmount = amountOut
-			bestQuote = &quote
+	return merged, nil
+}
+
+// fetchPoolData enriches the on-chain pools with subgraph data. The subgraph
+// is optional: once its circuit breaker is open the quote proceeds with
+// on-chain data only instead of waiting on further calls.
+func (u *QuoteUsecase) fetchPoolData(ctx context.Context, fromTokenAddr, toTokenAddr string, pools map[string]string) map[string]*domain.PoolData {
+	poolDataMap := make(map[string]*domain.PoolData)
+
+	if u.poolGraph != nil {
+		for _, poolAddress := range pools {
+			if poolData, ok := u.poolGraph.Pool(poolAddress); ok {
+				poolDataMap[strings.ToLower(poolAddress)] = poolData
+			}
 		}
 	}
 
-	if bestQuote == nil {
-		return domain.QuoteResponse{}, fmt.Errorf("failed to get quotes from any pool")
+	if u.graphService == nil || len(poolDataMap) == len(pools) {
+		return poolDataMap
 	}
 
-	bestAmountAdjusted := u.adjustFromDecimals(bestAmount, toTokenInfo.Decimals)
+	graphPools, err := u.graphService.GetPoolsByTokenPair(ctx, fromTokenAddr, toTokenAddr)
+	if errors.Is(err, domain.ErrCircuitOpen) {
+		slog.DebugContext(ctx, "Ignoring subgraph", "reason", "circuit open", "from", fromTokenAddr, "to", toTokenAddr)
+		return poolDataMap
+	}
+	if err == nil {
+		for _, poolData := range graphPools {
+			poolDataMap[strings.ToLower(poolData.ID)] = poolData
+		}
+	} else {
+		slog.WarnContext(ctx, "Ignoring subgraph error", "reason", "pair lookup failed", "error", err, "from", fromTokenAddr, "to", toTokenAddr)
+	}
 
-	response := domain.QuoteResponse{
-		FromToken:  req.From,
-		ToToken:    req.To,
-		FromAmount: req.Amount,
-		ToAmount:   bestAmountAdjusted.String(),
-		BestQuote:  *bestQuote,
-		AllQuotes:  allQuotes,
+	for dexName, poolAddress := range pools {
+		poolLower := strings.ToLower(poolAddress)
+		if _, exists := poolDataMap[poolLower]; exists {
+			continue
+		}
+		poolData, err := u.graphService.GetPoolData(ctx, poolAddress)
+		if errors.Is(err, domain.ErrCircuitOpen) {
+			slog.DebugContext(ctx, "Ignoring subgraph", "reason", "circuit open", "pool", poolAddress, "dex", dexName)
+			break
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "Ignoring subgraph error", "reason", "pool lookup failed", "error", err, "pool", poolAddress, "dex", dexName)
+			continue
+		}
+		poolDataMap[poolLower] = poolData
 	}
 
-	return response, nil
+	return poolDataMap
 }
 
 func (u *QuoteUsecase) parseAmount(amountStr string) (*big.Int, error) {
 	amountStr = strings.TrimSpace(amountStr)
 
 	if amountStr == "" {
-		return nil, fmt.Errorf("amount cannot be empty")
+		return nil, domain.NewError(domain.CodeInvalidInput, "amount cannot be empty", nil)
 	}
 
 	amount, ok := new(big.Int).SetString(amountStr, 10)
 	if !ok {
-		return nil, fmt.Errorf("invalid amount format: %s", amountStr)
+		return nil, domain.NewError(domain.CodeInvalidInput, fmt.Sprintf("invalid amount format: %s", amountStr), nil)
 	}
 
 	if amount.Cmp(big.NewInt(0)) <= 0 {
-		return nil, fmt.Errorf("amount must be positive")
+		return nil, domain.NewError(domain.CodeInvalidInput, "amount must be positive", nil)
 	}
 
 	return amount, nil
@@ -175,10 +480,11 @@ func (u *QuoteUsecase) adjustFromDecimals(amount *big.Int, decimals uint8) *big.
 }
 
 func (u *QuoteUsecase) buildPoolInfo(poolData *domain.PoolData) *domain.PoolInfo {
-	isActive := poolData.ReserveUSD >= u.minTVL
+	isActive := poolData.ReserveUSD >= u.settings.MinTVL()
 
 	return &domain.PoolInfo{
 		TVL:          fmt.Sprintf("%.2f", poolData.ReserveUSD),
+		TVLSource:    domain.TVLSourceSubgraph,
 		Volume24h:    fmt.Sprintf("%.2f", poolData.Volume24hUSD),
 		Fees24h:      fmt.Sprintf("%.2f", poolData.Fees24hUSD),
 		Reserve0:     poolData.Reserve0,
@@ -189,6 +495,38 @@ func (u *QuoteUsecase) buildPoolInfo(poolData *domain.PoolData) *domain.PoolInfo
 	}
 }
 
+func (u *QuoteUsecase) onChainPoolInfo(ctx context.Context, poolAddress string) (*domain.PoolInfo, float64, error) {
+	reserves, err := u.ethereumService.GetPoolReserves(ctx, poolAddress)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tvl, err := u.tvlEstimator.EstimatePoolTVL(ctx, reserves)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	info := &domain.PoolInfo{
+		TVL:       u.formatFloat(tvl),
+		TVLSource: domain.TVLSourceOnChain,
+		Reserve0:  reserves.Reserve0.String(),
+		Reserve1:  reserves.Reserve1.String(),
+		IsActive:  tvl >= u.settings.MinTVL(),
+	}
+
+	// Report reserves in token units, matching what the subgraph returns.
+	if token0Info, err := u.ethereumService.GetTokenInfo(ctx, reserves.Token0); err == nil {
+		info.Token0Symbol = token0Info.Symbol
+		info.Reserve0 = formatUnits(reserves.Reserve0, token0Info.Decimals)
+	}
+	if token1Info, err := u.ethereumService.GetTokenInfo(ctx, reserves.Token1); err == nil {
+		info.Token1Symbol = token1Info.Symbol
+		info.Reserve1 = formatUnits(reserves.Reserve1, token1Info.Decimals)
+	}
+
+	return info, tvl, nil
+}
+
 func (u *QuoteUsecase) formatFloat(f float64) string {
 	return strconv.FormatFloat(f, 'f', 2, 64)
 }
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+func TestCandidatePoolsAddsOnlyV2GraphPools(t *testing.T) {
+	chain := &stubChain{pools: []stubPool{
+		{dex: "uniswap_v2", address: "0x0a", reserves: &domain.PoolReserves{Token0: testUSDC, Token1: testWETH}, poolType: domain.PoolTypeUniswapV2},
+		{address: "0x0b", poolType: domain.PoolTypeUniswapV2},
+		{address: "0x0c", poolType: domain.PoolTypeUniswapV3},
+		{address: "0x0d", poolType: domain.PoolTypeUnknown},
+	}}
+	graph := &stubPoolGraph{pools: []*domain.PoolData{
+		{ID: "0x0A", Token0: testUSDC, Token1: testWETH, DEX: "uniswap_v2"},
+		{ID: "0x0b", Token0: testUSDC, Token1: testWETH, DEX: "fork"},
+		{ID: "0x0c", Token0: testUSDC, Token1: testWETH, DEX: "uniswap_v3"},
+		{ID: "0x0d", Token0: testUSDC, Token1: testWETH, DEX: "unknown"},
+	}}
+	u := newTestUsecase(chain, graph, nil)
+
+	pools, err := u.candidatePools(context.Background(), testWETH, testUSDC)
+	if err != nil {
+		t.Fatalf("candidatePools: %v", err)
+	}
+
+	want := map[string]string{"uniswap_v2": "0x0a", "fork": "0x0b"}
+	if !maps.Equal(pools, want) {
+		t.Errorf("pools = %v, want %v", pools, want)
+	}
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"math"
+	"sync/atomic"
+
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/ethereum"
+)
+
+// Settings holds the usecase parameters a configuration reload may change
+// while requests are in flight. One instance is shared by every usecase.
+type Settings struct {
+	minTVL atomic.Uint64
+	tokens *ethereum.TokenRegistry
+}
+
+func NewSettings(minTVL float64, tokens *ethereum.TokenRegistry) *Settings {
+	s := &Settings{tokens: tokens}
+	s.SetMinTVL(minTVL)
+	return s
+}
+
+// MinTVL is the USD liquidity below which pools are not quoted.
+func (s *Settings) MinTVL() float64 {
+	return math.Float64frombits(s.minTVL.Load())
+}
+
+func (s *Settings) SetMinTVL(minTVL float64) {
+	s.minTVL.Store(math.Float64bits(minTVL))
+}
+
+// Tokens is the registry token symbols are resolved against.
+func (s *Settings) Tokens() *ethereum.TokenRegistry {
+	return s.tokens
+}
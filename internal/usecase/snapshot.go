@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"golang.org/x/sync/singleflight"
+)
+
+// snapshotService memoises chain reads for the lifetime of one batch. Items
+// that touch the same pool or token share a single upstream call, and
+// failures are remembered too so every item sees the same outcome.
+type snapshotService struct {
+	next domain.EthereumServiceInterface
+
+	group   singleflight.Group
+	mu      sync.Mutex
+	results map[string]snapshotResult
+}
+
+type snapshotResult struct {
+	value interface{}
+	err   error
+}
+
+func newSnapshotService(next domain.EthereumServiceInterface) *snapshotService {
+	return &snapshotService{
+		next:    next,
+		results: make(map[string]snapshotResult),
+	}
+}
+
+func (s *snapshotService) memo(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	s.mu.Lock()
+	result, ok := s.results[key]
+	s.mu.Unlock()
+	if ok {
+		return result.value, result.err
+	}
+
+	value, err, _ := s.group.Do(key, func() (interface{}, error) {
+		value, err := fetch()
+		s.mu.Lock()
+		s.results[key] = snapshotResult{value: value, err: err}
+		s.mu.Unlock()
+		return value, err
+	})
+	return value, err
+}
+
+func (s *snapshotService) GetPoolReserves(ctx context.Context, poolAddress string) (*domain.PoolReserves, error) {
+	value, err := s.memo("reserves|"+strings.ToLower(poolAddress), func() (interface{}, error) {
+		return s.next.GetPoolReserves(ctx, poolAddress)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*domain.PoolReserves), nil
+}
+
+func (s *snapshotService) GetTokenInfo(ctx context.Context, tokenAddress string) (*domain.TokenInfo, error) {
+	value, err := s.memo("token|"+strings.ToLower(tokenAddress), func() (interface{}, error) {
+		return s.next.GetTokenInfo(ctx, tokenAddress)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*domain.TokenInfo), nil
+}
+
+func (s *snapshotService) FindPool(ctx context.Context, dexName, tokenA, tokenB string) (string, error) {
+	value, err := s.memo("pool|"+dexName+"|"+pairKey(tokenA, tokenB), func() (interface{}, error) {
+		return s.next.FindPool(ctx, dexName, tokenA, tokenB)
+	})
+	if err != nil {
+		return "", err
+	}
+	return value.(string), nil
+}
+
+func (s *snapshotService) FindAllPools(ctx context.Context, tokenA, tokenB string) (map[string]string, error) {
+	value, err := s.memo("pools|"+pairKey(tokenA, tokenB), func() (interface{}, error) {
+		return s.next.FindAllPools(ctx, tokenA, tokenB)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]string), nil
+}
+
+func (s *snapshotService) DetectPoolType(ctx context.Context, poolAddress string) (domain.PoolType, error) {
+	value, err := s.memo("type|"+strings.ToLower(poolAddress), func() (interface{}, error) {
+		return s.next.DetectPoolType(ctx, poolAddress)
+	})
+	if err != nil {
+		return domain.PoolTypeUnknown, err
+	}
+	return value.(domain.PoolType), nil
+}
+
+// GetQuoteForPool prices the trade from the memoised reserves instead of
+// delegating, which would re-read them for every amount.
+func (s *snapshotService) GetQuoteForPool(ctx context.Context, poolAddress, tokenIn string, amountIn *big.Int) (*big.Int, error) {
+	reserves, err := s.GetPoolReserves(ctx, poolAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.EqualFold(tokenIn, reserves.Token0):
+		return calculateAMMOutput(amountIn, reserves.Reserve0, reserves.Reserve1)
+	case strings.EqualFold(tokenIn, reserves.Token1):
+		return calculateAMMOutput(amountIn, reserves.Reserve1, reserves.Reserve0)
+	default:
+		return nil, domain.NewError(domain.CodeInvalidInput, "token "+tokenIn+" not found in pool", nil)
+	}
+}
+
+func (s *snapshotService) BlockNumber(ctx context.Context) (uint64, error) {
+	return s.next.BlockNumber(ctx)
+}
+
+func pairKey(tokenA, tokenB string) string {
+	tokens := []string{strings.ToLower(tokenA), strings.ToLower(tokenB)}
+	sort.Strings(tokens)
+	return tokens[0] + "|" + tokens[1]
+}
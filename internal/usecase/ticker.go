@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+)
+
+// pricePrecision is the number of decimals in reported prices.
+const pricePrecision = 18
+
+// PriceTick quotes one unit of From to find the best pool and reports that
+// pool's mid price, so the figure is free of trade size and fee effects.
+func (u *QuoteUsecase) PriceTick(ctx context.Context, req domain.PriceTickRequest) (domain.PriceTick, error) {
+	quote, err := u.Quote(ctx, domain.QuoteRequest{From: req.From, To: req.To, Amount: "1"})
+	if err != nil {
+		return domain.PriceTick{}, err
+	}
+
+	reserves, err := u.ethereumService.GetPoolReserves(ctx, quote.BestQuote.Pool)
+	if err != nil {
+		return domain.PriceTick{}, domain.NewUpstreamError("failed to get pool reserves", err)
+	}
+
+	token0Info, err := u.ethereumService.GetTokenInfo(ctx, reserves.Token0)
+	if err != nil {
+		return domain.PriceTick{}, domain.NewUpstreamError("failed to get token info", err)
+	}
+	token1Info, err := u.ethereumService.GetTokenInfo(ctx, reserves.Token1)
+	if err != nil {
+		return domain.PriceTick{}, domain.NewUpstreamError("failed to get token info", err)
+	}
+
+	// price of token0 in token1 = (reserve1 / 10^dec1) / (reserve0 / 10^dec0)
+	price := midPrice(reserves.Reserve0, token0Info.Decimals, reserves.Reserve1, token1Info.Decimals)
+	if price == nil {
+		return domain.PriceTick{}, domain.NewError(domain.CodeNoLiquidity, "best pool has empty reserves", nil)
+	}
+	fromTokenAddr, err := resolveTokenAddress(u.settings.Tokens(), req.From)
+	if err != nil {
+		return domain.PriceTick{}, err
+	}
+	if !strings.EqualFold(reserves.Token0, fromTokenAddr) {
+		price.Inv(price)
+	}
+
+	return domain.PriceTick{
+		FromToken:   req.From,
+		ToToken:     req.To,
+		Price:       price.FloatString(pricePrecision),
+		BestDEX:     quote.BestQuote.DEX,
+		Pool:        quote.BestQuote.Pool,
+		BlockNumber: reserves.BlockNumber,
+	}, nil
+}
+
+// midPrice returns how many whole units of the second token one whole unit
+// of the first is worth, or nil when either reserve is empty.
+func midPrice(reserveBase *big.Int, baseDecimals uint8, reserveQuote *big.Int, quoteDecimals uint8) *big.Rat {
+	if reserveBase.Sign() <= 0 || reserveQuote.Sign() <= 0 {
+		return nil
+	}
+
+	base := new(big.Rat).SetFrac(reserveBase, pow10(baseDecimals))
+	quote := new(big.Rat).SetFrac(reserveQuote, pow10(quoteDecimals))
+	return new(big.Rat).Quo(quote, base)
+}
+
+func pow10(decimals uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/ethereum"
+)
+
+type TokensUsecase struct {
+	ethereumService domain.EthereumServiceInterface
+	tokens          *ethereum.TokenRegistry
+}
+
+func NewTokensUsecase(ethereumService domain.EthereumServiceInterface, tokens *ethereum.TokenRegistry) *TokensUsecase {
+	return &TokensUsecase{
+		ethereumService: ethereumService,
+		tokens:          tokens,
+	}
+}
+
+// Tokens lists the registry with metadata read from each token contract.
+func (u *TokensUsecase) Tokens(ctx context.Context, req domain.TokensRequest) (domain.TokensResponse, error) {
+	symbols := u.tokens.Symbols()
+
+	tokens := make([]domain.Token, 0, len(symbols))
+	for _, symbol := range symbols {
+		address := u.tokens.Address(symbol)
+
+		// The native asset has no contract to read.
+		if symbol == ethereum.NativeTokenSymbol {
+			tokens = append(tokens, domain.Token{
+				Symbol:   symbol,
+				Name:     "Ether",
+				Address:  address,
+				Decimals: 18,
+			})
+			continue
+		}
+
+		info, err := u.ethereumService.GetTokenInfo(ctx, address)
+		if err != nil {
+			return domain.TokensResponse{}, domain.NewUpstreamError(fmt.Sprintf("failed to get token info for %s", symbol), err)
+		}
+
+		tokens = append(tokens, domain.Token{
+			Symbol:   symbol,
+			Name:     info.Name,
+			Address:  address,
+			Decimals: info.Decimals,
+		})
+	}
+
+	return domain.TokensResponse{
+		Tokens: tokens,
+		Count:  len(tokens),
+	}, nil
+}
+
+func tokenFromInfo(info *domain.TokenInfo) domain.Token {
+	return domain.Token{
+		Symbol:   info.Symbol,
+		Name:     info.Name,
+		Address:  info.Address,
+		Decimals: info.Decimals,
+	}
+}
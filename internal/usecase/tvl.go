@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/DiDinar5/mini-dex-aggregator/domain"
+	"github.com/DiDinar5/mini-dex-aggregator/infrastructure/ethereum"
+)
+
+// stablecoins are priced at exactly $1 and anchor every other USD price.
+var stablecoins = []string{"USDC", "USDT", "DAI"}
+
+// TVLEstimator prices a pool's reserves in USD from on-chain data only. Each
+// token is valued through an anchor pool: stablecoins at $1, WETH through its
+// USDC pool, and anything else through whichever of its WETH and USDC pools
+// holds more USD, so a dust pool cannot outweigh a deep one.
+type TVLEstimator struct {
+	ethereumService domain.EthereumServiceInterface
+	tokens          *ethereum.TokenRegistry
+
+	mu     sync.Mutex
+	prices map[string]tokenPrice
+}
+
+type tokenPrice struct {
+	usd         float64
+	blockNumber uint64
+}
+
+func NewTVLEstimator(ethereumService domain.EthereumServiceInterface, tokens *ethereum.TokenRegistry) *TVLEstimator {
+	return &TVLEstimator{
+		ethereumService: ethereumService,
+		tokens:          tokens,
+		prices:          make(map[string]tokenPrice),
+	}
+}
+
+// EstimatePoolTVL returns the USD value of both reserves. When only one side
+// can be priced the pool is assumed balanced, as constant-product pools are.
+func (t *TVLEstimator) EstimatePoolTVL(ctx context.Context, reserves *domain.PoolReserves) (float64, error) {
+	value0, err0 := t.reserveValueUSD(ctx, reserves.Token0, reserves.Reserve0, reserves.BlockNumber)
+	value1, err1 := t.reserveValueUSD(ctx, reserves.Token1, reserves.Reserve1, reserves.BlockNumber)
+
+	switch {
+	case err0 == nil && err1 == nil:
+		return value0 + value1, nil
+	case err0 == nil:
+		return 2 * value0, nil
+	case err1 == nil:
+		return 2 * value1, nil
+	default:
+		return 0, fmt.Errorf("failed to price either side of the pool: %w", err0)
+	}
+}
+
+func (t *TVLEstimator) reserveValueUSD(ctx context.Context, token string, reserve *big.Int, blockNumber uint64) (float64, error) {
+	price, err := t.TokenPriceUSD(ctx, token, blockNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	tokenInfo, err := t.ethereumService.GetTokenInfo(ctx, token)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get token info: %w", err)
+	}
+
+	return toFloat(reserve, tokenInfo.Decimals) * price, nil
+}
+
+// TokenPriceUSD returns the USD price of a token. Prices are memoised per
+// block so a quote touching several pools prices each anchor only once.
+func (t *TVLEstimator) TokenPriceUSD(ctx context.Context, token string, blockNumber uint64) (float64, error) {
+	if t.isStablecoin(token) {
+		return 1, nil
+	}
+
+	key := strings.ToLower(token)
+
+	t.mu.Lock()
+	cached, ok := t.prices[key]
+	t.mu.Unlock()
+	if ok && cached.blockNumber >= blockNumber {
+		return cached.usd, nil
+	}
+
+	price, err := t.priceViaAnchors(ctx, token, blockNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	t.mu.Lock()
+	t.prices[key] = tokenPrice{usd: price, blockNumber: blockNumber}
+	t.mu.Unlock()
+
+	return price, nil
+}
+
+func (t *TVLEstimator) priceViaAnchors(ctx context.Context, token string, blockNumber uint64) (float64, error) {
+	weth := t.tokens.Address("WETH")
+	usdc := t.tokens.Address("USDC")
+
+	if strings.EqualFold(token, weth) {
+		return t.relativePrice(ctx, weth, usdc)
+	}
+
+	inUSDC, usdcDepth, usdcErr := t.anchorPrice(ctx, token, usdc)
+
+	inWETH, wethDepth, err := t.anchorPrice(ctx, token, weth)
+	if err != nil {
+		if usdcErr == nil {
+			return inUSDC, nil
+		}
+		return 0, fmt.Errorf("no anchor pool for token %s: %w", token, err)
+	}
+
+	wethUSD, err := t.TokenPriceUSD(ctx, weth, blockNumber)
+	if err != nil {
+		if usdcErr == nil {
+			return inUSDC, nil
+		}
+		return 0, err
+	}
+
+	if usdcErr == nil && usdcDepth >= wethDepth*wethUSD {
+		return inUSDC, nil
+	}
+	return inWETH * wethUSD, nil
+}
+
+// relativePrice returns how many units of quote one unit of base is worth,
+// read from the deepest direct pool between the two.
+func (t *TVLEstimator) relativePrice(ctx context.Context, base, quote string) (float64, error) {
+	price, _, err := t.anchorPrice(ctx, base, quote)
+	return price, err
+}
+
+// anchorPrice is relativePrice that also reports the depth of the pool it
+// read, as its quote reserve in whole units.
+func (t *TVLEstimator) anchorPrice(ctx context.Context, base, quote string) (float64, float64, error) {
+	pools, err := t.ethereumService.FindAllPools(ctx, base, quote)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	baseInfo, err := t.ethereumService.GetTokenInfo(ctx, base)
+	if err != nil {
+		return 0, 0, err
+	}
+	quoteInfo, err := t.ethereumService.GetTokenInfo(ctx, quote)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var price, depth float64
+	for _, poolAddress := range pools {
+		reserves, err := t.ethereumService.GetPoolReserves(ctx, poolAddress)
+		if err != nil {
+			continue
+		}
+
+		baseReserve, quoteReserve := reserves.Reserve0, reserves.Reserve1
+		if !strings.EqualFold(reserves.Token0, base) {
+			baseReserve, quoteReserve = quoteReserve, baseReserve
+		}
+
+		baseAmount := toFloat(baseReserve, baseInfo.Decimals)
+		quoteAmount := toFloat(quoteReserve, quoteInfo.Decimals)
+		if baseAmount == 0 || quoteAmount == 0 {
+			continue
+		}
+
+		if quoteAmount > depth {
+			depth = quoteAmount
+			price = quoteAmount / baseAmount
+		}
+	}
+
+	if depth == 0 {
+		return 0, 0, fmt.Errorf("no pool between %s and %s", base, quote)
+	}
+
+	return price, depth, nil
+}
+
+func (t *TVLEstimator) isStablecoin(token string) bool {
+	for _, symbol := range stablecoins {
+		if strings.EqualFold(token, t.tokens.Address(symbol)) {
+			return true
+		}
+	}
+	return false
+}
+
+func toFloat(amount *big.Int, decimals uint8) float64 {
+	if amount == nil {
+		return 0
+	}
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), big.NewFloat(math.Pow10(int(decimals)))).Float64()
+	return f
+}
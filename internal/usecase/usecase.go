@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/DiDinar5/mini-dex-aggregator/domain"
 )
@@ -9,6 +10,9 @@ import (
 type CombinedUsecase struct {
 	estimateUsecase *EstimateUsecase
 	quoteUsecase    *QuoteUsecase
+	poolsUsecase    *PoolsUsecase
+	tokensUsecase   *TokensUsecase
+	batchUsecase    *BatchUsecase
 }
 
 func (c *CombinedUsecase) Estimate(ctx context.Context, req domain.EstimateRequest) (domain.EstimateResponse, error) {
@@ -19,11 +23,61 @@ func (c *CombinedUsecase) Quote(ctx context.Context, req domain.QuoteRequest) (d
 	return c.quoteUsecase.Quote(ctx, req)
 }
 
-func NewUsecase(ethereumService domain.EthereumServiceInterface, graphService domain.TheGraphServiceInterface, minTVL float64) domain.UsecaseInterface {
+func (c *CombinedUsecase) Pools(ctx context.Context, req domain.PoolsRequest) (domain.PoolsResponse, error) {
+	return c.poolsUsecase.Pools(ctx, req)
+}
+
+func (c *CombinedUsecase) PoolDetail(ctx context.Context, req domain.PoolDetailRequest) (domain.PoolDetail, error) {
+	return c.poolsUsecase.PoolDetail(ctx, req)
+}
+
+func (c *CombinedUsecase) Tokens(ctx context.Context, req domain.TokensRequest) (domain.TokensResponse, error) {
+	return c.tokensUsecase.Tokens(ctx, req)
+}
+
+func (c *CombinedUsecase) BatchQuote(ctx context.Context, req domain.BatchRequest) (domain.BatchResponse, error) {
+	return c.batchUsecase.BatchQuote(ctx, req)
+}
+
+func (c *CombinedUsecase) PriceTick(ctx context.Context, req domain.PriceTickRequest) (domain.PriceTick, error) {
+	return c.quoteUsecase.PriceTick(ctx, req)
+}
+
+func (c *CombinedUsecase) Price(ctx context.Context, req domain.PriceRequest) (domain.PriceResponse, error) {
+	return c.quoteUsecase.Price(ctx, req)
+}
+
+func (c *CombinedUsecase) SwapQuote(ctx context.Context, from, to string, amountIn *big.Int) (domain.SwapQuote, error) {
+	return c.quoteUsecase.SwapQuote(ctx, from, to, amountIn)
+}
+
+func (c *CombinedUsecase) SwapEstimate(ctx context.Context, pool, src, dst string, amountIn *big.Int) (domain.SwapEstimate, error) {
+	return c.estimateUsecase.SwapEstimate(ctx, pool, src, dst, amountIn)
+}
+
+// NewUsecase wires every usecase. gasSource may be nil, in which case quotes
+// are ranked by gross output.
+func NewUsecase(ethereumService domain.EthereumServiceInterface, graphService domain.TheGraphServiceInterface, poolGraph domain.PoolGraphInterface, settings *Settings, gasSource domain.GasPriceSource) *CombinedUsecase {
 	return &CombinedUsecase{
 		estimateUsecase: NewEstimateUsecase(ethereumService),
-		quoteUsecase:    NewQuoteUsecase(ethereumService, graphService, minTVL),
+		quoteUsecase:    NewQuoteUsecase(ethereumService, graphService, poolGraph, settings, gasSource),
+		poolsUsecase:    NewPoolsUsecase(ethereumService, graphService, poolGraph, settings),
+		tokensUsecase:   NewTokensUsecase(ethereumService, settings.Tokens()),
+		batchUsecase:    NewBatchUsecase(ethereumService, graphService, poolGraph, settings, gasSource),
+	}
+}
+
+// pinBlock pins ctx to the current head unless it already is pinned.
+func pinBlock(ctx context.Context, ethereumService domain.EthereumServiceInterface) (context.Context, error) {
+	if _, ok := domain.BlockNumberFromContext(ctx); ok {
+		return ctx, nil
+	}
+
+	blockNumber, err := ethereumService.BlockNumber(ctx)
+	if err != nil {
+		return ctx, domain.NewUpstreamError("failed to get current block number", err)
 	}
+	return domain.WithBlockNumber(ctx, blockNumber), nil
 }
 
 const (